@@ -0,0 +1,58 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"elasticsearch-sample/backend/internal/config"
+	"elasticsearch-sample/backend/internal/logging"
+)
+
+// ローカル開発用に auth.hs256_secret (AUTH_HS256_SECRET) で署名したトークンを発行する
+func main() {
+	// コマンドライン引数の解析
+	var (
+		subject = flag.String("sub", "admin123", "トークンのsubject(UserのUID)")
+		name    = flag.String("name", "", "トークンのnameクレーム(表示名)")
+		email   = flag.String("email", "", "トークンのemailクレーム")
+		ttl     = flag.Duration("ttl", 24*time.Hour, "トークンの有効期間")
+	)
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal("❌ 設定の読み込みに失敗しました", "error", err)
+	}
+	if cfg.Auth.HS256Secret == "" {
+		logging.Fatal("❌ auth.hs256_secret (AUTH_HS256_SECRET) が設定されていません")
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"sub": *subject,
+		"iat": now.Unix(),
+		"exp": now.Add(*ttl).Unix(),
+	}
+	if *name != "" {
+		claims["name"] = *name
+	}
+	if *email != "" {
+		claims["email"] = *email
+	}
+	if cfg.Auth.Issuer != "" {
+		claims["iss"] = cfg.Auth.Issuer
+	}
+	if cfg.Auth.Audience != "" {
+		claims["aud"] = cfg.Auth.Audience
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(cfg.Auth.HS256Secret.Value()))
+	if err != nil {
+		logging.Fatal("❌ トークンの署名に失敗しました", "error", err)
+	}
+
+	fmt.Println(token)
+}
@@ -3,10 +3,12 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
+	"elasticsearch-sample/backend/internal/config"
 	"elasticsearch-sample/backend/internal/infrastructure/db"
+	"elasticsearch-sample/backend/internal/logging"
 )
 
 func main() {
@@ -18,15 +20,25 @@ func main() {
 	)
 	flag.Parse()
 
+	// 設定の読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal("❌ 設定の読み込みに失敗しました", "error", err)
+	}
+	logging.Setup(cfg.Log)
+
 	// データベースに接続
-	db.ConnectDB()
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		logging.Fatal("❌ データベース接続に失敗しました", "error", err)
+	}
+	defer db.Close(database)
 
 	// マイグレーション状態の表示
 	if *showStatus {
-		executedMigrations, err := db.GetMigrationStatus()
+		executedMigrations, err := db.GetMigrationStatus(database)
 		if err != nil {
-			log.Printf("❌ マイグレーション状態の取得に失敗しました: %v", err)
-			os.Exit(1)
+			logging.Fatal("❌ マイグレーション状態の取得に失敗しました", "error", err)
 		}
 
 		if len(executedMigrations) == 0 {
@@ -42,17 +54,15 @@ func main() {
 
 	// マイグレーションの実行
 	if *migrateTo != "" {
-		if err := db.MigrateTo(*migrateTo); err != nil {
-			log.Printf("❌ マイグレーションに失敗しました: %v", err)
-			os.Exit(1)
+		if err := db.MigrateTo(database, *migrateTo); err != nil {
+			logging.Fatal("❌ マイグレーションに失敗しました", "error", err)
 		}
-		log.Printf("✅ マイグレーション '%s' まで実行が完了しました", *migrateTo)
+		slog.Info("✅ マイグレーションの実行が完了しました", "migration", *migrateTo)
 	} else if *migrateAll {
-		if err := db.RunMigrations(); err != nil {
-			log.Printf("❌ マイグレーションに失敗しました: %v", err)
-			os.Exit(1)
+		if err := db.RunMigrations(database); err != nil {
+			logging.Fatal("❌ マイグレーションに失敗しました", "error", err)
 		}
-		log.Printf("✅ 全てのマイグレーションの実行が完了しました")
+		slog.Info("✅ 全てのマイグレーションの実行が完了しました")
 	} else {
 		fmt.Println("❌ マイグレーション対象を指定してください")
 		fmt.Println("使用例:")
@@ -2,41 +2,56 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
 	"time"
 
-	"elasticsearch-sample/backend/internal/domain/repository"
-	"elasticsearch-sample/backend/internal/infrastructure/db"
-	"elasticsearch-sample/backend/internal/infrastructure/es"
-	"elasticsearch-sample/backend/internal/usecase"
+	"elasticsearch-sample/backend/internal/app"
+	"elasticsearch-sample/backend/internal/config"
+	"elasticsearch-sample/backend/internal/logging"
 )
 
 func main() {
+	// 設定の読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal("❌ 設定の読み込みに失敗しました", "error", err)
+	}
+	logging.Setup(cfg.Log)
+
+	// os.Exitは遅延実行を飛ばすため、接続を閉じてから終了する
+	if err := run(cfg); err != nil {
+		slog.Error("❌ 検索エンジンの再構築に失敗しました", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("✅ 検索エンジンの再構築が正常に完了しました。")
+}
+
+func run(cfg *config.Config) (err error) {
 	// タイムアウト付きのコンテキストを作成（10分間）
 	// 大量データの移行を想定し、少し長めに設定します
-	_, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
-	// Infrastructure初期化
-	db.ConnectDB()
-	esClient, err := es.NewClient()
+	// 依存関係の初期化 (添付ファイルは扱わないため、保存先は初期化しない)
+	application, err := app.New(cfg, app.WithoutStorage())
 	if err != nil {
-		log.Fatalf("❌ Elasticsearchへの接続に失敗しました: %v", err)
+		return fmt.Errorf("初期化に失敗しました: %w", err)
 	}
+	if err := application.Start(ctx); err != nil {
+		return fmt.Errorf("起動に失敗しました: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, application.Stop(context.Background()))
+	}()
 
-	// Repository初期化
-	articleDBRepo := repository.NewArticleRepository(db.DB)
-	articleSearchRepo := es.NewArticleSearchRepository(esClient)
-
-	// Usecase初期化
-	articleUsecase := usecase.NewArticleUsecase(articleDBRepo, articleSearchRepo)
-
-	log.Println("🚀 検索エンジンの再構築を開始します...")
+	slog.Info("🚀 検索エンジンの再構築を開始します...")
 
 	// 再構築処理の実行
-	if err := articleUsecase.ReindexSearchEngine(); err != nil {
-		log.Fatalf("❌ 再構築中にエラーが発生しました: %v", err)
+	if err := application.ArticleUsecase.ReindexSearchEngine(); err != nil {
+		return fmt.Errorf("再構築中にエラーが発生しました: %w", err)
 	}
-
-	log.Println("✅ 検索エンジンの再構築が正常に完了しました。")
+	return nil
 }
@@ -3,10 +3,12 @@ package main
 import (
 	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 
+	"elasticsearch-sample/backend/internal/config"
 	"elasticsearch-sample/backend/internal/infrastructure/db"
+	"elasticsearch-sample/backend/internal/logging"
 )
 
 func main() {
@@ -18,15 +20,25 @@ func main() {
 	)
 	flag.Parse()
 
+	// 設定の読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal("❌ 設定の読み込みに失敗しました", "error", err)
+	}
+	logging.Setup(cfg.Log)
+
 	// データベースに接続
-	db.ConnectDB()
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		logging.Fatal("❌ データベース接続に失敗しました", "error", err)
+	}
+	defer db.Close(database)
 
 	// マイグレーション状態の表示
 	if *showStatus {
-		executedMigrations, err := db.GetMigrationStatus()
+		executedMigrations, err := db.GetMigrationStatus(database)
 		if err != nil {
-			log.Printf("❌ マイグレーション状態の取得に失敗しました: %v", err)
-			os.Exit(1)
+			logging.Fatal("❌ マイグレーション状態の取得に失敗しました", "error", err)
 		}
 
 		if len(executedMigrations) == 0 {
@@ -42,17 +54,15 @@ func main() {
 
 	// ロールバックの実行
 	if *rollbackTo != "" {
-		if err := db.RollbackTo(*rollbackTo); err != nil {
-			log.Printf("❌ ロールバックに失敗しました: %v", err)
-			os.Exit(1)
+		if err := db.RollbackTo(database, *rollbackTo); err != nil {
+			logging.Fatal("❌ ロールバックに失敗しました", "error", err)
 		}
-		log.Printf("✅ マイグレーション '%s' までロールバックが完了しました", *rollbackTo)
+		slog.Info("✅ ロールバックが完了しました", "migration", *rollbackTo)
 	} else if *rollbackLast {
-		if err := db.RollbackLast(); err != nil {
-			log.Printf("❌ ロールバックに失敗しました: %v", err)
-			os.Exit(1)
+		if err := db.RollbackLast(database); err != nil {
+			logging.Fatal("❌ ロールバックに失敗しました", "error", err)
 		}
-		log.Printf("✅ 最後のマイグレーションのロールバックが完了しました")
+		slog.Info("✅ 最後のマイグレーションのロールバックが完了しました")
 	} else {
 		fmt.Println("❌ ロールバック対象を指定してください")
 		fmt.Println("使用例:")
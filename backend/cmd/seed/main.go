@@ -2,60 +2,76 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
 	"time"
 
-	"elasticsearch-sample/backend/internal/domain/repository"
-	"elasticsearch-sample/backend/internal/infrastructure/db"
-	"elasticsearch-sample/backend/internal/infrastructure/es"
-	"elasticsearch-sample/backend/internal/usecase"
+	"elasticsearch-sample/backend/internal/app"
+	"elasticsearch-sample/backend/internal/config"
+	"elasticsearch-sample/backend/internal/logging"
 )
 
 func main() {
+	// 設定の読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal("❌ 設定の読み込みに失敗しました", "error", err)
+	}
+	logging.Setup(cfg.Log)
+
+	// os.Exitは遅延実行を飛ばすため、接続を閉じてから終了する
+	if err := run(cfg); err != nil {
+		slog.Error("❌ シードデータの投入に失敗しました", "error", err)
+		os.Exit(1)
+	}
+	slog.Info("✅ シードデータの投入が正常に完了しました。")
+}
+
+func run(cfg *config.Config) (err error) {
 	// タイムアウト付きのコンテキストを作成（5分間）
 	// 大量データの投入を想定し、少し長めに設定します
-	_, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
 	defer cancel()
 
-	// Infrastructure初期化
-	db.ConnectDB()
-	esClient, err := es.NewClient()
+	// 依存関係の初期化 (添付ファイルは扱わないため、保存先は初期化しない)
+	application, err := app.New(cfg, app.WithoutStorage())
 	if err != nil {
-		log.Fatalf("❌ Elasticsearchへの接続に失敗しました: %v", err)
+		return fmt.Errorf("初期化に失敗しました: %w", err)
 	}
+	if err := application.Start(ctx); err != nil {
+		return fmt.Errorf("起動に失敗しました: %w", err)
+	}
+	defer func() {
+		err = errors.Join(err, application.Stop(context.Background()))
+	}()
 
-	// Repository初期化
-	userDBRepo := repository.NewUserRepository(db.DB)
-	articleDBRepo := repository.NewArticleRepository(db.DB)
-	articleSearchRepo := es.NewArticleSearchRepository(esClient)
-
-	// Usecase初期化
-	userUsecase := usecase.NewUserUsecase(userDBRepo)
-	articleUsecase := usecase.NewArticleUsecase(articleDBRepo, articleSearchRepo)
+	userUsecase := application.UserUsecase
+	articleUsecase := application.ArticleUsecase
 
-	log.Println("🚀 シードデータの投入を開始します...")
+	slog.Info("🚀 シードデータの投入を開始します...")
 
 	// ユーザーデータのシード投入
 	users, err := userUsecase.SeedUsers()
 	if err != nil {
-		log.Fatalf("❌ ユーザーデータのシード投入中にエラーが発生しました: %v", err)
+		return fmt.Errorf("ユーザーデータのシード投入中にエラーが発生しました: %w", err)
 	}
-	log.Printf("✅ %d件のユーザーデータを投入しました。", len(users))
+	slog.Info("✅ ユーザーデータを投入しました。", "count", len(users))
 
 	// 記事データのシード投入（最初のユーザーに紐づけ）
 	if len(users) == 0 {
-		log.Fatalf("❌ シード投入用のユーザーが存在しません。")
+		return errors.New("シード投入用のユーザーが存在しません")
 	}
 	articles, err := articleUsecase.SeedArticles(users[0].ID)
 	if err != nil {
-		log.Fatalf("❌ 記事データのシード投入中にエラーが発生しました: %v", err)
+		return fmt.Errorf("記事データのシード投入中にエラーが発生しました: %w", err)
 	}
-	log.Printf("✅ %d件の記事データを投入しました。", len(articles))
+	slog.Info("✅ 記事データを投入しました。", "count", len(articles))
 
 	// 記事データを検索エンジンにインデックス
 	if err := articleUsecase.ReindexSearchEngine(); err != nil {
-		log.Fatalf("❌ 記事データの検索エンジンへのインデックス中にエラーが発生しました: %v", err)
+		return fmt.Errorf("記事データの検索エンジンへのインデックス中にエラーが発生しました: %w", err)
 	}
-
-	log.Println("✅ シードデータの投入が正常に完了しました。")
+	return nil
 }
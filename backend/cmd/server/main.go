@@ -1,58 +1,129 @@
 package main
 
 import (
+	"context"
 	"elasticsearch-sample/backend/graph"
-	"elasticsearch-sample/backend/internal/domain/repository"
-	"elasticsearch-sample/backend/internal/infrastructure/db"
-	"elasticsearch-sample/backend/internal/infrastructure/es"
-	"elasticsearch-sample/backend/internal/usecase"
-	"log"
+	"elasticsearch-sample/backend/internal/app"
+	"elasticsearch-sample/backend/internal/config"
+	"elasticsearch-sample/backend/internal/infrastructure/auth"
+	"elasticsearch-sample/backend/internal/infrastructure/storage"
+	"elasticsearch-sample/backend/internal/logging"
+	"elasticsearch-sample/backend/internal/metrics"
+	"elasticsearch-sample/backend/internal/ratelimit"
+	"elasticsearch-sample/backend/internal/worker"
+	"errors"
+	"log/slog"
+	"net"
 	"net/http"
-	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
+	"github.com/gorilla/websocket"
 	"github.com/rs/cors"
 	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 )
 
-const defaultPort = "8080"
-
 func main() {
-	port := os.Getenv("SERVER_PORT")
-	if port == "" {
-		port = defaultPort
+	// 設定の読み込み
+	cfg, err := config.Load()
+	if err != nil {
+		logging.Fatal("設定の読み込み失敗", "error", err)
+	}
+	logging.Setup(cfg.Log)
+	slog.Info("⚙️ 設定を読み込みました", "config", cfg.String())
+
+	// 認証設定の読み込み (接続を開く前に行い、失敗しても閉じ忘れがないようにする)
+	tokenVerifier, err := auth.NewTokenVerifier(cfg.Auth)
+	if err != nil {
+		logging.Fatal("認証設定の読み込み失敗", "error", err)
 	}
 
-	// Infrastructure初期化
-	db.ConnectDB()
-	esClient, err := es.NewClient()
+	// 依存関係の初期化
+	application, err := app.New(cfg)
 	if err != nil {
-		log.Fatalf("Elasticsearch接続失敗 (設定やプラグインを確認してください): %v", err)
+		logging.Fatal("初期化失敗 (設定やElasticsearchのプラグインを確認してください)", "error", err)
 	}
+	articleUsecase := application.ArticleUsecase
+	userUsecase := application.UserUsecase
+	apiKeyUsecase := application.APIKeyUsecase
+	commentUsecase := application.CommentUsecase
+
+	// 予約公開ワーカー
+	application.AppendWorker(worker.NewPeriodicWorker("scheduled-publisher", cfg.Scheduler.Interval, func(ctx context.Context) error {
+		published, err := articleUsecase.PublishScheduledArticles(ctx, time.Now())
+		if published > 0 {
+			slog.InfoContext(ctx, "✅ 予約記事を公開しました", "count", published)
+		}
+		return err
+	}))
 
-	// Repository初期化
-	articleDBRepo := repository.NewArticleRepository(db.DB)
-	articleSearchRepo := es.NewArticleSearchRepository(esClient)
-	userDBRepo := repository.NewUserRepository(db.DB)
+	// ゴミ箱を空にするワーカー(保持期間を過ぎた記事を完全に削除する)
+	application.AppendWorker(worker.NewPeriodicWorker("trash-purger", cfg.Trash.PurgeInterval, func(ctx context.Context) error {
+		purged, err := articleUsecase.PurgeDeletedArticles(ctx, time.Now().Add(-cfg.Trash.Retention))
+		if purged > 0 {
+			slog.InfoContext(ctx, "🗑️ ゴミ箱の記事を完全に削除しました", "count", purged)
+		}
+		return err
+	}))
 
-	// Usecase初期化
-	articleUsecase := usecase.NewArticleUsecase(articleDBRepo, articleSearchRepo)
-	userUsecase := usecase.NewUserUsecase(userDBRepo)
+	// いいね数の同期ワーカー(検索結果の並び順に反映する)
+	application.AppendWorker(worker.NewPeriodicWorker("like-count-sync", cfg.Elasticsearch.LikeCountSyncInterval, func(ctx context.Context) error {
+		synced, err := application.LikeUsecase.SyncLikeCounts(ctx)
+		if synced > 0 {
+			slog.InfoContext(ctx, "✅ いいね数を検索エンジンに反映しました", "count", synced)
+		}
+		return err
+	}))
 
-	srv := handler.NewDefaultServer(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{
-		ArticleUsecase: articleUsecase,
-		UserUsecase:    userUsecase,
-	}}))
+	srv := handler.New(graph.NewExecutableSchema(graph.Config{Resolvers: &graph.Resolver{
+		ArticleUsecase:    articleUsecase,
+		UserUsecase:       userUsecase,
+		APIKeyUsecase:     apiKeyUsecase,
+		CommentUsecase:    commentUsecase,
+		AttachmentUsecase: application.AttachmentUsecase,
+		LikeUsecase:       application.LikeUsecase,
+		BookmarkUsecase:   application.BookmarkUsecase,
+		ArticleEvents:     application.ArticleEvents,
+	}, Complexity: graph.NewComplexityRoot()}))
 
+	srv.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: graph.WebsocketCheckOrigin(cfg.Server.CORSAllowedOrigins),
+		},
+		InitFunc: graph.WebsocketInitFunc(tokenVerifier, userUsecase),
+	})
 	srv.AddTransport(transport.Options{})
 	srv.AddTransport(transport.GET{})
 	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{
+		MaxUploadSize: int64(cfg.Server.MaxBodyBytes + cfg.Storage.MaxUploadBytes),
+		MaxMemory:     int64(cfg.Server.MaxBodyBytes),
+	})
 
 	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+	srv.SetErrorPresenter(graph.ErrorPresenter)
+	srv.SetRecoverFunc(graph.RecoverFunc)
+
+	srv.Use(graph.NewMetrics(cfg.GraphQL.MetricOperations))
+	srv.Use(graph.Tracing{})
+	srv.Use(graph.NewQueryLimit(graph.QueryLimitConfig{
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+	}))
+
+	rateLimiter := graph.NewRateLimiter(ratelimit.NewMemoryStore(), rateLimitConfig(cfg.RateLimit))
+	srv.Use(rateLimiter)
+	srv.Use(graph.NewDataLoaders(userUsecase, application.LikeUsecase))
 
 	srv.Use(extension.Introspection{})
 	srv.Use(extension.AutomaticPersistedQuery{
@@ -60,15 +131,116 @@ func main() {
 	})
 
 	c := cors.New(cors.Options{
-		AllowedOrigins:   []string{"*"},
+		AllowedOrigins:   cfg.Server.CORSAllowedOrigins,
 		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"*"},
 		AllowCredentials: true,
+		ExposedHeaders:   []string{logging.RequestIDHeader},
 	})
 
-	http.Handle("/", c.Handler(playground.Handler("GraphQL playground", "/query")))
-	http.Handle("/query", graph.AuthMiddleware()(srv))
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", application.Health.LivenessHandler())
+	mux.Handle("/readyz", application.Health.ReadinessHandler())
+	mux.Handle("/metrics", metrics.Handler())
+	if cfg.Server.Playground {
+		mux.Handle("/", c.Handler(playground.Handler("GraphQL playground", "/query")))
+	}
+	// ローカルに保存した添付ファイルの配信
+	if local, ok := application.Blobs.(*storage.LocalStore); ok {
+		mux.Handle(local.MountPath(), http.StripPrefix(strings.TrimSuffix(local.MountPath(), "/"), local))
+	}
+	// 認証の前にクライアントIPごとに制限し(不正な認証情報の総当たり対策)、認証の後にユーザーごとに操作の種類で制限する
+	mux.Handle("/query", graph.BodyLimitMiddleware(int64(cfg.Server.MaxBodyBytes), int64(cfg.Storage.MaxUploadBytes))(rateLimiter.IPMiddleware()(graph.AuthMiddleware(tokenVerifier, userUsecase, apiKeyUsecase)(rateLimiter.Middleware()(srv)))))
+
+	// サブスクリプション(WebSocket)はShutdownの対象外のため、停止時にこのContextをキャンセルして終了させる
+	baseCtx, cancelBaseCtx := context.WithCancel(context.Background())
+	defer cancelBaseCtx()
+
+	server := &http.Server{
+		Addr:              ":" + strconv.Itoa(cfg.Server.Port),
+		Handler:           tracingHandler(logging.RequestIDMiddleware(mux)),
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+		BaseContext:       func(net.Listener) context.Context { return baseCtx },
+	}
+	serverErr := make(chan error, 1)
 
-	log.Printf("connect to http://localhost:%s/ for GraphQL playground", port)
-	log.Fatal(http.ListenAndServe(":"+port, nil))
+	// HTTPサーバーは最後に登録し、停止時はワーカーやDBより先にリクエストの受付を止める
+	application.Append(app.Hook{
+		Name: "http-server",
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+			go func() {
+				if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+					serverErr <- err
+				}
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			// 新しい接続の受付を止め、処理中のリクエストの完了を待つ
+			application.Health.Drain()
+			err := server.Shutdown(ctx)
+			cancelBaseCtx()
+			return err
+		},
+	})
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := application.Start(ctx); err != nil {
+		logging.Fatal("起動失敗", "error", err)
+	}
+	if cfg.Server.Playground {
+		slog.Info("connect to GraphQL playground", "url", "http://localhost"+server.Addr+"/")
+	}
+
+	select {
+	case <-ctx.Done():
+		slog.Info("🔄 停止シグナルを受信しました。処理中のリクエストの完了を待っています...")
+	case err := <-serverErr:
+		slog.Error("❌ サーバーが停止しました", "error", err)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+	if err := application.Stop(shutdownCtx); err != nil {
+		logging.Fatal("❌ 停止処理に失敗しました", "error", err)
+	}
+	slog.Info("✅ サーバーを停止しました")
+}
+
+// tracingHandler 受信したtraceparentヘッダーを引き継いでリクエストのスパンを作成する
+// ヘルスチェックとメトリクスの収集はトレースの対象外
+func tracingHandler(next http.Handler) http.Handler {
+	return otelhttp.NewHandler(next, "http.server",
+		otelhttp.WithFilter(func(r *http.Request) bool {
+			switch r.URL.Path {
+			case "/healthz", "/readyz", "/metrics":
+				return false
+			}
+			return true
+		}),
+		otelhttp.WithSpanNameFormatter(func(operation string, r *http.Request) string {
+			return r.Method + " " + r.URL.Path
+		}),
+	)
+}
+
+// rateLimitConfig 設定のレート制限をGraphQLの拡張の設定に変換する
+func rateLimitConfig(cfg config.RateLimitConfig) graph.RateLimitConfig {
+	return graph.RateLimitConfig{
+		Search:            ratelimit.PerMinute(cfg.Search.PerMinute, cfg.Search.Burst),
+		Mutation:          ratelimit.PerMinute(cfg.Mutation.PerMinute, cfg.Mutation.Burst),
+		Query:             ratelimit.PerMinute(cfg.Query.PerMinute, cfg.Query.Burst),
+		IP:                ratelimit.PerMinute(cfg.IP.PerMinute, cfg.IP.Burst),
+		TrustForwardedFor: cfg.TrustForwardedFor,
+		TrustedProxies:    cfg.TrustedProxies,
+	}
 }
@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	model "elasticsearch-sample/backend/graph/model"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	entity "elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/usecase"
+)
+
+func (r *Resolver) APIKey() APIKeyResolver { return &aPIKeyResolver{r} }
+
+type aPIKeyResolver struct{ *Resolver }
+
+// ========================
+// 汎用関数
+// ========================
+
+var apiKeyScopes = map[model.APIKeyScope]string{
+	model.APIKeyScopeArticlesRead:  entity.ScopeArticlesRead,
+	model.APIKeyScopeArticlesWrite: entity.ScopeArticlesWrite,
+	model.APIKeyScopeSearchAdmin:   entity.ScopeSearchAdmin,
+}
+
+func ToModelAPIKey(apiKey entity.APIKey) *model.APIKey {
+	scopes := []model.APIKeyScope{}
+	for _, scope := range apiKey.Scopes {
+		for modelScope, s := range apiKeyScopes {
+			if s == scope {
+				scopes = append(scopes, modelScope)
+			}
+		}
+	}
+
+	return &model.APIKey{
+		ID:         fmt.Sprintf("%d", apiKey.ID),
+		Name:       apiKey.Name,
+		Prefix:     apiKey.Prefix,
+		Scopes:     scopes,
+		ExpiresAt:  formatOptionalTime(apiKey.ExpiresAt),
+		LastUsedAt: formatOptionalTime(apiKey.LastUsedAt),
+		RevokedAt:  formatOptionalTime(apiKey.RevokedAt),
+		CreatedAt:  apiKey.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UserID:     fmt.Sprintf("%d", apiKey.UserID),
+	}
+}
+
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	formatted := t.Format("2006-01-02T15:04:05Z07:00")
+	return &formatted
+}
+
+// =======================
+// Resolver
+// ========================
+
+func (r *aPIKeyResolver) Owner(ctx context.Context, obj *model.APIKey) (*model.User, error) {
+	userID, err := strconv.ParseUint(obj.UserID, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	// DataLoaderで同一リクエスト内のユーザー取得をまとめる
+	user, err := r.loaders(ctx).UserByID.Load(ctx, uint(userID))
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelUser(*user), nil
+}
+
+// ========================
+// Mutation
+// ========================
+
+func (r *mutationResolver) CreateAPIKey(ctx context.Context, input model.CreateAPIKeyInput) (*model.CreateAPIKeyPayload, error) {
+	// 管理者ユーザーの取得
+	user, err := r.requireAdmin(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し
+	createInput := usecase.CreateAPIKeyInput{
+		UserID: user.ID,
+		Name:   input.Name,
+	}
+	for _, scope := range input.Scopes {
+		createInput.Scopes = append(createInput.Scopes, apiKeyScopes[scope])
+	}
+	if input.ExpiresAt != nil {
+		expiresAt, err := time.Parse(time.RFC3339, *input.ExpiresAt)
+		if err != nil {
+			return nil, apperror.Validation("expiresAt must be RFC3339 format")
+		}
+		createInput.ExpiresAt = &expiresAt
+	}
+	apiKey, rawKey, err := r.APIKeyUsecase.CreateAPIKey(ctx, createInput)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.CreateAPIKeyPayload{
+		APIKey: ToModelAPIKey(*apiKey),
+		Key:    rawKey,
+	}, nil
+}
+
+func (r *mutationResolver) RevokeAPIKey(ctx context.Context, input model.RevokeAPIKeyInput) (*model.APIKey, error) {
+	// 管理者ユーザーの確認
+	if _, err := r.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し
+	apiKeyID, err := parseID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	apiKey, err := r.APIKeyUsecase.RevokeAPIKey(ctx, apiKeyID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelAPIKey(*apiKey), nil
+}
+
+// ========================
+// Query
+// ========================
+
+func (r *queryResolver) APIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	// 管理者ユーザーの確認
+	if _, err := r.requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し
+	apiKeys, err := r.APIKeyUsecase.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// モデル変換
+	result := []*model.APIKey{}
+	for _, apiKey := range apiKeys {
+		result = append(result, ToModelAPIKey(*apiKey))
+	}
+
+	return result, nil
+}
@@ -4,9 +4,13 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"time"
 
 	model "elasticsearch-sample/backend/graph/model"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/domain/event"
 	entity "elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/markdown"
 	"elasticsearch-sample/backend/internal/usecase"
 )
 
@@ -14,6 +18,9 @@ func (r *Resolver) Article() ArticleResolver { return &articleResolver{r} }
 
 type articleResolver struct{ *Resolver }
 
+// 記事の抜粋の文字数
+const excerptLength = 140
+
 // ========================
 // 汎用関数
 // ========================
@@ -21,24 +28,58 @@ func ToModelArticle(article entity.Article) *model.Article {
 	return &model.Article{
 		ID:        fmt.Sprintf("%d", article.ID),
 		Title:     article.Title,
+		Slug:      article.Slug,
 		Content:   &article.Content,
 		Status:    model.ArticleStatus(article.Status),
 		UserID:    fmt.Sprintf("%d", article.UserID),
 		CreatedAt: article.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt: article.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		PublishAt: formatOptionalTime(article.PublishAt),
+		DeletedAt: formatOptionalTime(deletedAt(article)),
+		Tags:      article.TagNames(),
+		LikeCount: int32(article.LikeCount),
+	}
+}
+
+// deletedAt 記事を削除した日時(削除されていない場合はnil)
+func deletedAt(article entity.Article) *time.Time {
+	if !article.DeletedAt.Valid {
+		return nil
+	}
+	return &article.DeletedAt.Time
+}
+
+// articleContent 記事の本文(未設定の場合は空文字)
+func articleContent(article *model.Article) string {
+	if article.Content == nil {
+		return ""
 	}
+	return *article.Content
 }
 
 // =======================
 // Resolver
 // ========================
+func (r *articleResolver) ContentHTML(ctx context.Context, obj *model.Article) (string, error) {
+	return markdown.HTML(articleContent(obj)), nil
+}
+
+func (r *articleResolver) Excerpt(ctx context.Context, obj *model.Article) (string, error) {
+	return markdown.Excerpt(markdown.PlainText(articleContent(obj)), excerptLength), nil
+}
+
+func (r *articleResolver) ReadingMinutes(ctx context.Context, obj *model.Article) (int32, error) {
+	return int32(markdown.ReadingMinutes(markdown.PlainText(articleContent(obj)))), nil
+}
+
 func (r *articleResolver) Author(ctx context.Context, obj *model.Article) (*model.User, error) {
 	userID, err := strconv.ParseUint(obj.UserID, 10, 32)
 	if err != nil {
 		return nil, err
 	}
 
-	user, err := r.UserUsecase.GetUserByID(ctx, uint(userID))
+	// DataLoaderで同一リクエスト内のユーザー取得をまとめる
+	user, err := r.loaders(ctx).UserByID.Load(ctx, uint(userID))
 	if err != nil {
 		return nil, err
 	}
@@ -54,7 +95,10 @@ func (r *mutationResolver) CreateArticle(ctx context.Context, input model.Create
 	// ログインユーザーIDの取得
 	userUID, ok := GetUserUIDFromContext(ctx)
 	if !ok {
-		return nil, fmt.Errorf("authentication required")
+		return nil, apperror.Unauthenticated("authentication required")
+	}
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
 	}
 	// ログインユーザー情報の取得
 	user, err := r.UserUsecase.GetUserByUID(ctx, userUID)
@@ -68,6 +112,7 @@ func (r *mutationResolver) CreateArticle(ctx context.Context, input model.Create
 		Content: *input.Content,
 		Status:  "draft",
 		UserID:  user.ID,
+		Tags:    input.Tags,
 	}
 	createdArticle, err := r.ArticleUsecase.CreateArticle(ctx, createInput)
 	if err != nil {
@@ -77,22 +122,73 @@ func (r *mutationResolver) CreateArticle(ctx context.Context, input model.Create
 	return ToModelArticle(*createdArticle), nil
 }
 
-func (r *mutationResolver) PublishArticle(ctx context.Context, input model.PublishArticleInput) (*model.Article, error) {
+func (r *mutationResolver) UpdateArticle(ctx context.Context, input model.UpdateArticleInput) (*model.Article, error) {
 	// ログインユーザーIDの取得
-	_, ok := GetUserUIDFromContext(ctx)
+	userUID, ok := GetUserUIDFromContext(ctx)
 	if !ok {
-		return nil, fmt.Errorf("authentication required")
+		return nil, apperror.Unauthenticated("authentication required")
+	}
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+	user, err := r.UserUsecase.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return nil, err
+	}
+
+	articleID, err := parseID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	// 記事を編集できるのは作成者のみ
+	article, err := r.ArticleUsecase.GetArticleByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if article.UserID != user.ID {
+		return nil, apperror.Forbidden("only the author can update this article")
 	}
 
 	// Usecaseの呼び出し
-	articleID, err := strconv.ParseUint(input.ID, 10, 32)
+	updateInput := usecase.UpdateArticleInput{
+		ArticleID: articleID,
+		Title:     input.Title,
+		Content:   input.Content,
+		EditorID:  user.ID,
+	}
+	// 未指定(null)の場合はタグを変更せず、空のリストの場合はすべて外す
+	if input.Tags != nil {
+		updateInput.Tags = &input.Tags
+	}
+	updatedArticle, err := r.ArticleUsecase.UpdateArticle(ctx, updateInput)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelArticle(*updatedArticle), nil
+}
+
+func (r *mutationResolver) PublishArticle(ctx context.Context, input model.PublishArticleInput) (*model.Article, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+
+	articleID, err := parseID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	// 記事の状態を変更できるのは作成者のみ
+	user, err := r.requireArticleAuthor(ctx, articleID)
 	if err != nil {
 		return nil, err
 	}
+
+	// Usecaseの呼び出し
 	status := "published"
 	updatedArticle, err := r.ArticleUsecase.UpdateArticle(ctx, usecase.UpdateArticleInput{
-		ArticleID: uint(articleID),
+		ArticleID: articleID,
 		Status:    &status,
+		EditorID:  user.ID,
 	})
 	if err != nil {
 		return nil, err
@@ -102,21 +198,26 @@ func (r *mutationResolver) PublishArticle(ctx context.Context, input model.Publi
 }
 
 func (r *mutationResolver) ArchiveArticle(ctx context.Context, input model.ArchiveArticleInput) (*model.Article, error) {
-	// ログインユーザーIDの取得
-	_, ok := GetUserUIDFromContext(ctx)
-	if !ok {
-		return nil, fmt.Errorf("authentication required")
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
 	}
 
-	// Usecaseの呼び出し
-	articleID, err := strconv.ParseUint(input.ID, 10, 32)
+	articleID, err := parseID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	// 記事の状態を変更できるのは作成者のみ
+	user, err := r.requireArticleAuthor(ctx, articleID)
 	if err != nil {
 		return nil, err
 	}
+
+	// Usecaseの呼び出し
 	status := "archived"
 	updatedArticle, err := r.ArticleUsecase.UpdateArticle(ctx, usecase.UpdateArticleInput{
-		ArticleID: uint(articleID),
+		ArticleID: articleID,
 		Status:    &status,
+		EditorID:  user.ID,
 	})
 	if err != nil {
 		return nil, err
@@ -125,49 +226,131 @@ func (r *mutationResolver) ArchiveArticle(ctx context.Context, input model.Archi
 	return ToModelArticle(*updatedArticle), nil
 }
 
+func (r *mutationResolver) ScheduleArticlePublication(ctx context.Context, input model.ScheduleArticlePublicationInput) (*model.Article, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+	articleID, err := parseID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	// 予約公開できるのは作成者のみ
+	user, err := r.requireArticleAuthor(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し
+	publishAt, err := time.Parse(time.RFC3339, input.PublishAt)
+	if err != nil {
+		return nil, apperror.Validation("publishAt must be RFC3339 format")
+	}
+	updatedArticle, err := r.ArticleUsecase.ScheduleArticlePublication(ctx, articleID, user.ID, publishAt)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelArticle(*updatedArticle), nil
+}
+
+func (r *mutationResolver) ReindexSearchEngine(ctx context.Context) (bool, error) {
+	// APIキーはsearch:adminスコープ、ユーザーは管理者のみ実行可能
+	if _, ok := GetAPIKeyScopesFromContext(ctx); ok {
+		if err := requireScope(ctx, entity.ScopeSearchAdmin); err != nil {
+			return false, err
+		}
+	} else if _, err := r.requireAdmin(ctx); err != nil {
+		return false, err
+	}
+
+	// Usecaseの呼び出し
+	if err := r.ArticleUsecase.ReindexSearchEngine(); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
 // ========================
 // Query
 // ========================
 
 func (r *queryResolver) Articles(ctx context.Context) ([]*model.Article, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
+	// 公開中の記事と、ログインユーザー自身の記事のみ返す
+	viewerID, err := r.viewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	// Usecaseの呼び出し
-	articles := []*model.Article{}
-	var page int = 1
-	var pageSize int = 100
-	for {
-		fetchedArticles, err := r.ArticleUsecase.ListArticles(ctx, page, pageSize)
-		if err != nil {
-			return nil, err
-		}
-		if len(fetchedArticles) == 0 {
-			break
-		}
-		for _, article := range fetchedArticles {
-			articles = append(articles, ToModelArticle(*article))
-		}
-		page++
+	// ページングがないため、すべての記事を読み込まないよう件数に上限を設ける(コストの計算もこの件数で行う)
+	fetchedArticles, err := r.ArticleUsecase.ListArticles(ctx, viewerID, 1, maxArticlesListSize)
+	if err != nil {
+		return nil, err
+	}
+	articles := make([]*model.Article, 0, len(fetchedArticles))
+	for _, article := range fetchedArticles {
+		articles = append(articles, ToModelArticle(*article))
 	}
 
 	return articles, nil
 }
 
 func (r *queryResolver) Article(ctx context.Context, id string) (*model.Article, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
 	// Usecaseの呼び出し
-	articleID, err := strconv.ParseUint(id, 10, 32)
+	articleID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	article, err := r.requireVisibleArticle(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelArticle(*article), nil
+}
+
+func (r *queryResolver) ArticleBySlug(ctx context.Context, authorUID string, slug string) (*model.Article, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し(古いスラッグの場合も記事を返し、リダイレクトはフロントエンドで行う)
+	author, err := r.UserUsecase.GetUserByUID(ctx, authorUID)
+	if err != nil {
+		return nil, err
+	}
+	article, err := r.ArticleUsecase.GetArticleBySlug(ctx, author.ID, slug)
 	if err != nil {
 		return nil, err
 	}
-	article, err := r.ArticleUsecase.GetArticleByID(ctx, uint(articleID))
+	// 公開前・アーカイブ済みの記事は作成者にのみ返し、それ以外には存在しない記事として扱う
+	viewerID, err := r.viewerID(ctx)
 	if err != nil {
 		return nil, err
 	}
+	if !isVisibleArticle(article, viewerID) {
+		return nil, apperror.NotFound("article not found")
+	}
 
 	return ToModelArticle(*article), nil
 }
 
-func (r *queryResolver) SearchArticles(ctx context.Context, keyword string) ([]*model.Article, error) {
+func (r *queryResolver) SearchArticles(ctx context.Context, keyword string, tags []string) ([]*model.Article, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
 	// Usecaseの呼び出し
-	articles, err := r.ArticleUsecase.SearchArticles(ctx, keyword)
+	articles, err := r.ArticleUsecase.SearchArticles(ctx, keyword, tags)
 	if err != nil {
 		return nil, err
 	}
@@ -180,3 +363,94 @@ func (r *queryResolver) SearchArticles(ctx context.Context, keyword string) ([]*
 
 	return result, nil
 }
+
+func (r *queryResolver) PopularTags(ctx context.Context, first *int32) ([]*model.TagCount, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し
+	limit := defaultPopularTags
+	if first != nil {
+		limit = int(*first)
+	}
+	tagCounts, err := r.ArticleUsecase.PopularTags(ctx, limit)
+	if err != nil {
+		return nil, err
+	}
+
+	// モデル変換
+	result := make([]*model.TagCount, 0, len(tagCounts))
+	for _, tagCount := range tagCounts {
+		result = append(result, &model.TagCount{
+			Name:  tagCount.Name,
+			Count: int32(tagCount.Count),
+		})
+	}
+
+	return result, nil
+}
+
+// ========================
+// Subscription
+// ========================
+
+func (r *subscriptionResolver) ArticlePublished(ctx context.Context) (<-chan *model.Article, error) {
+	events := r.ArticleEvents.Subscribe(ctx)
+
+	ch := make(chan *model.Article)
+	go func() {
+		defer close(ch)
+		for ev := range events {
+			if ev.Type != event.ArticlePublished {
+				continue
+			}
+			select {
+			case ch <- ToModelArticle(ev.Article):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+func (r *subscriptionResolver) ArticleUpdated(ctx context.Context, id string) (<-chan *model.Article, error) {
+	articleID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	// 閲覧できない記事は、存在しない記事と同じエラーにする(下書きのIDの存在を確かめられないように)
+	if _, err := r.requireVisibleArticle(ctx, articleID); err != nil {
+		return nil, err
+	}
+
+	// 購読後に非公開になった記事の変更は本人にのみ配信する
+	viewerID, err := r.viewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	events := r.ArticleEvents.Subscribe(ctx)
+
+	ch := make(chan *model.Article)
+	go func() {
+		defer close(ch)
+		for ev := range events {
+			if ev.Article.ID != articleID {
+				continue
+			}
+			if ev.Article.Status != "published" && ev.Article.UserID != viewerID {
+				continue
+			}
+			select {
+			case ch <- ToModelArticle(ev.Article):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
@@ -0,0 +1,211 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	model "elasticsearch-sample/backend/graph/model"
+	entity "elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/textdiff"
+	"elasticsearch-sample/backend/internal/usecase"
+)
+
+func (r *Resolver) ArticleRevision() ArticleRevisionResolver { return &articleRevisionResolver{r} }
+
+type articleRevisionResolver struct{ *Resolver }
+
+// ========================
+// 汎用関数
+// ========================
+
+var diffModes = map[model.DiffMode]textdiff.Mode{
+	model.DiffModeLine: textdiff.ModeLine,
+	model.DiffModeWord: textdiff.ModeWord,
+}
+
+var diffOperations = map[textdiff.Operation]model.DiffOperation{
+	textdiff.OperationEqual:  model.DiffOperationEqual,
+	textdiff.OperationInsert: model.DiffOperationInsert,
+	textdiff.OperationDelete: model.DiffOperationDelete,
+}
+
+func ToModelArticleRevision(revision entity.ArticleRevision) *model.ArticleRevision {
+	result := &model.ArticleRevision{
+		ID:        fmt.Sprintf("%d", revision.ID),
+		Version:   int32(revision.Version),
+		Title:     revision.Title,
+		Content:   &revision.Content,
+		Status:    model.ArticleStatus(revision.Status),
+		CreatedAt: revision.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}
+	if revision.EditorID != nil {
+		editorID := fmt.Sprintf("%d", *revision.EditorID)
+		result.EditorID = &editorID
+	}
+	return result
+}
+
+func toModelDiffChunks(chunks []textdiff.Chunk) []*model.DiffChunk {
+	result := make([]*model.DiffChunk, 0, len(chunks))
+	for _, chunk := range chunks {
+		result = append(result, &model.DiffChunk{
+			Operation: diffOperations[chunk.Operation],
+			Text:      chunk.Text,
+		})
+	}
+	return result
+}
+
+// =======================
+// Resolver
+// ========================
+
+func (r *articleResolver) Revisions(ctx context.Context, obj *model.Article, first *int32, after *string) (*model.ArticleRevisionConnection, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
+	articleID, err := parseID(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := pageSize(first)
+	if err != nil {
+		return nil, err
+	}
+	beforeVersion, err := DecodeCursor("revision", after)
+	if err != nil {
+		return nil, err
+	}
+
+	// 履歴は作成者のみ閲覧可能(他のユーザーには空の一覧を返す)
+	empty := &model.ArticleRevisionConnection{Edges: []*model.ArticleRevisionEdge{}, PageInfo: &model.PageInfo{}}
+	userUID, ok := GetUserUIDFromContext(ctx)
+	if !ok {
+		return empty, nil
+	}
+	user, err := r.UserUsecase.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return nil, err
+	}
+	if obj.UserID != fmt.Sprintf("%d", user.ID) || limit == 0 {
+		return empty, nil
+	}
+
+	// Usecaseの呼び出し(次ページの有無を判定するため1件多く取得)
+	revisions, totalCount, err := r.ArticleUsecase.ListRevisions(ctx, usecase.ListArticleRevisionsInput{
+		ArticleID:     articleID,
+		BeforeVersion: int(beforeVersion),
+		Limit:         limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// モデル変換
+	hasNextPage := len(revisions) > limit
+	if hasNextPage {
+		revisions = revisions[:limit]
+	}
+	edges := make([]*model.ArticleRevisionEdge, 0, len(revisions))
+	for _, revision := range revisions {
+		edges = append(edges, &model.ArticleRevisionEdge{
+			Cursor: EncodeCursor("revision", uint(revision.Version)),
+			Node:   ToModelArticleRevision(*revision),
+		})
+	}
+	pageInfo := &model.PageInfo{HasNextPage: hasNextPage}
+	if len(edges) > 0 {
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &model.ArticleRevisionConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: int32(totalCount),
+	}, nil
+}
+
+func (r *articleRevisionResolver) Editor(ctx context.Context, obj *model.ArticleRevision) (*model.User, error) {
+	// システムによる更新(予約公開など)の場合は編集者なし
+	if obj.EditorID == nil {
+		return nil, nil
+	}
+	editorID, err := parseID(*obj.EditorID)
+	if err != nil {
+		return nil, err
+	}
+
+	// DataLoaderで同一リクエスト内のユーザー取得をまとめる
+	user, err := r.loaders(ctx).UserByID.Load(ctx, editorID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelUser(*user), nil
+}
+
+// ========================
+// Mutation
+// ========================
+
+func (r *mutationResolver) RestoreRevision(ctx context.Context, input model.RestoreRevisionInput) (*model.Article, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+
+	articleID, err := parseID(input.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	// 履歴から復元できるのは作成者のみ
+	user, err := r.requireArticleAuthor(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し
+	restoredArticle, err := r.ArticleUsecase.RestoreRevision(ctx, articleID, int(input.Version), user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelArticle(*restoredArticle), nil
+}
+
+// ========================
+// Query
+// ========================
+
+func (r *queryResolver) RevisionDiff(ctx context.Context, articleID string, from int32, to int32, mode *model.DiffMode) (*model.RevisionDiff, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
+	id, err := parseID(articleID)
+	if err != nil {
+		return nil, err
+	}
+	// 履歴を閲覧できるのは作成者のみ
+	if _, err := r.requireArticleAuthor(ctx, id); err != nil {
+		return nil, err
+	}
+
+	diffMode := textdiff.ModeLine
+	if mode != nil {
+		diffMode = diffModes[*mode]
+	}
+
+	// Usecaseの呼び出し
+	diff, err := r.ArticleUsecase.DiffRevisions(ctx, id, int(from), int(to), diffMode)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.RevisionDiff{
+		From:    ToModelArticleRevision(*diff.From),
+		To:      ToModelArticleRevision(*diff.To),
+		Title:   toModelDiffChunks(diff.Title),
+		Content: toModelDiffChunks(diff.Content),
+	}, nil
+}
@@ -0,0 +1,116 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	model "elasticsearch-sample/backend/graph/model"
+	entity "elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/usecase"
+)
+
+// ========================
+// 汎用関数
+// ========================
+// toModelAttachment 添付ファイルをURL付きのモデルに変換する
+func (r *Resolver) toModelAttachment(ctx context.Context, attachment entity.Attachment) (*model.Attachment, error) {
+	url, err := r.AttachmentUsecase.AttachmentURL(ctx, &attachment)
+	if err != nil {
+		return nil, err
+	}
+	return &model.Attachment{
+		ID:          fmt.Sprintf("%d", attachment.ID),
+		ArticleID:   fmt.Sprintf("%d", attachment.ArticleID),
+		Filename:    attachment.Filename,
+		ContentType: attachment.ContentType,
+		Size:        int32(attachment.Size),
+		URL:         url,
+		CreatedAt:   attachment.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+	}, nil
+}
+
+// =======================
+// Resolver
+// ========================
+
+func (r *articleResolver) Attachments(ctx context.Context, obj *model.Article) ([]*model.Attachment, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
+	articleID, err := parseID(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	attachments, err := r.AttachmentUsecase.ListAttachments(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// モデル変換
+	result := make([]*model.Attachment, 0, len(attachments))
+	for _, attachment := range attachments {
+		modelAttachment, err := r.toModelAttachment(ctx, *attachment)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, modelAttachment)
+	}
+	return result, nil
+}
+
+// ========================
+// Mutation
+// ========================
+
+func (r *mutationResolver) UploadAttachment(ctx context.Context, input model.UploadAttachmentInput) (*model.Attachment, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+	articleID, err := parseID(input.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	user, err := r.requireArticleAuthor(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し(形式・サイズはUsecaseで検証する)
+	attachment, err := r.AttachmentUsecase.UploadAttachment(ctx, usecase.UploadAttachmentInput{
+		ArticleID: articleID,
+		UserID:    user.ID,
+		Filename:  input.File.Filename,
+		File:      input.File.File,
+		Size:      input.File.Size,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.toModelAttachment(ctx, *attachment)
+}
+
+func (r *mutationResolver) DeleteAttachment(ctx context.Context, input model.DeleteAttachmentInput) (bool, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return false, err
+	}
+	attachmentID, err := parseID(input.ID)
+	if err != nil {
+		return false, err
+	}
+	attachment, err := r.AttachmentUsecase.GetAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		return false, err
+	}
+	if _, err := r.requireArticleAuthor(ctx, attachment.ArticleID); err != nil {
+		return false, err
+	}
+
+	// Usecaseの呼び出し
+	if err := r.AttachmentUsecase.DeleteAttachment(ctx, attachmentID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
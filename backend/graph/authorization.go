@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	entity "elasticsearch-sample/backend/internal/domain/model"
+)
+
+// requireScope APIキーで認証された場合に、指定したスコープが付与されているか確認する
+// ユーザーのトークンで認証された場合(または未ログイン)はスコープによる制限を受けない
+func requireScope(ctx context.Context, scope string) error {
+	scopes, ok := GetAPIKeyScopesFromContext(ctx)
+	if !ok || slices.Contains(scopes, scope) {
+		return nil
+	}
+	return apperror.Forbidden(fmt.Sprintf("api key is missing scope: %s", scope))
+}
+
+// requireAdmin ログインユーザーが管理者か確認する(APIキーでは管理操作を行えない)
+func (r *Resolver) requireAdmin(ctx context.Context) (*entity.User, error) {
+	if _, ok := GetAPIKeyScopesFromContext(ctx); ok {
+		return nil, apperror.Forbidden("api keys cannot perform admin operations")
+	}
+
+	userUID, ok := GetUserUIDFromContext(ctx)
+	if !ok {
+		return nil, apperror.Unauthenticated("authentication required")
+	}
+	user, err := r.UserUsecase.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.IsAdmin {
+		return nil, apperror.Forbidden("admin privileges required")
+	}
+
+	return user, nil
+}
+
+// currentUser ログインユーザーを取得する(未ログインの場合はエラー)
+func (r *Resolver) currentUser(ctx context.Context) (*entity.User, error) {
+	userUID, ok := GetUserUIDFromContext(ctx)
+	if !ok {
+		return nil, apperror.Unauthenticated("authentication required")
+	}
+	return r.UserUsecase.GetUserByUID(ctx, userUID)
+}
+
+// requireArticleAuthor ログインユーザーが記事の作成者か確認する
+func (r *Resolver) requireArticleAuthor(ctx context.Context, articleID uint) (*entity.User, error) {
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	article, err := r.ArticleUsecase.GetArticleByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if article.UserID != user.ID {
+		return nil, apperror.Forbidden("only the author of this article can perform this action")
+	}
+	return user, nil
+}
+
+// viewerID ログインユーザーのIDを返す(未ログインの場合は0)
+func (r *Resolver) viewerID(ctx context.Context) (uint, error) {
+	userUID, ok := GetUserUIDFromContext(ctx)
+	if !ok {
+		return 0, nil
+	}
+	user, err := r.UserUsecase.GetUserByUID(ctx, userUID)
+	if err != nil {
+		return 0, err
+	}
+	return user.ID, nil
+}
+
+// requireVisibleArticle 記事を取得する
+// 公開前・アーカイブ済みの記事は作成者にのみ返し、それ以外には存在しない記事として扱う
+func (r *Resolver) requireVisibleArticle(ctx context.Context, articleID uint) (*entity.Article, error) {
+	article, err := r.ArticleUsecase.GetArticleByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	viewerID, err := r.viewerID(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !isVisibleArticle(article, viewerID) {
+		return nil, apperror.NotFound("article not found")
+	}
+	return article, nil
+}
+
+// isVisibleArticle 記事が公開中、または閲覧者が作成者か
+func isVisibleArticle(article *entity.Article, viewerID uint) bool {
+	return article.Status == "published" || (viewerID != 0 && article.UserID == viewerID)
+}
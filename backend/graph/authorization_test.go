@@ -0,0 +1,84 @@
+package graph
+
+import (
+	"context"
+	"testing"
+
+	"elasticsearch-sample/backend/graph/model"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+)
+
+func TestArticleStatusMutationsRequireAuthor(t *testing.T) {
+	mutations := map[string]func(r *mutationResolver, ctx context.Context) (*model.Article, error){
+		"publishArticle": func(r *mutationResolver, ctx context.Context) (*model.Article, error) {
+			return r.PublishArticle(ctx, model.PublishArticleInput{ID: "1"})
+		},
+		"archiveArticle": func(r *mutationResolver, ctx context.Context) (*model.Article, error) {
+			return r.ArchiveArticle(ctx, model.ArchiveArticleInput{ID: "1"})
+		},
+	}
+	for name, mutate := range mutations {
+		t.Run(name, func(t *testing.T) {
+			resolver, articles := newTestResolver()
+			r := &mutationResolver{resolver}
+
+			if _, err := mutate(r, withUser("other")); !apperror.Is(err, apperror.CodeForbidden) {
+				t.Fatalf("non-author error = %v, want %s", err, apperror.CodeForbidden)
+			}
+			if len(articles.updated) != 0 {
+				t.Fatalf("article was updated by non-author: %+v", articles.updated)
+			}
+
+			if _, err := mutate(r, withUser("author")); err != nil {
+				t.Fatalf("author error = %v", err)
+			}
+			if len(articles.updated) != 1 {
+				t.Fatalf("updates = %d, want 1", len(articles.updated))
+			}
+		})
+	}
+}
+
+func TestArticleHidesUnpublishedFromNonAuthor(t *testing.T) {
+	resolver, _ := newTestResolver()
+	r := &queryResolver{resolver}
+
+	tests := []struct {
+		name    string
+		ctx     context.Context
+		wantErr apperror.Code
+	}{
+		{"anonymous", context.Background(), apperror.CodeNotFound},
+		{"other user", withUser("other"), apperror.CodeNotFound},
+		{"author", withUser("author"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article, err := r.Article(tt.ctx, "1")
+			if tt.wantErr != "" {
+				if !apperror.Is(err, tt.wantErr) {
+					t.Fatalf("Article() error = %v, want %s", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || article.ID != "1" {
+				t.Fatalf("Article() = %+v, %v", article, err)
+			}
+		})
+	}
+}
+
+func TestArticleUpdatedHidesUnpublishedFromNonAuthor(t *testing.T) {
+	resolver, _ := newTestResolver()
+	r := &subscriptionResolver{resolver}
+
+	// 存在しない記事と閲覧できない記事は同じエラーになる
+	_, missingErr := r.ArticleUpdated(withUser("other"), "999")
+	_, draftErr := r.ArticleUpdated(withUser("other"), "1")
+	if !apperror.Is(missingErr, apperror.CodeNotFound) || !apperror.Is(draftErr, apperror.CodeNotFound) {
+		t.Fatalf("errors = %v, %v, want both %s", missingErr, draftErr, apperror.CodeNotFound)
+	}
+	if missingErr.Error() != draftErr.Error() {
+		t.Errorf("error messages differ: %q, %q", missingErr, draftErr)
+	}
+}
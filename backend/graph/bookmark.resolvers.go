@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"context"
+
+	model "elasticsearch-sample/backend/graph/model"
+	entity "elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/usecase"
+)
+
+// =======================
+// Resolver
+// ========================
+
+func (r *userResolver) Bookmarks(ctx context.Context, obj *model.User, first *int32, after *string) (*model.BookmarkConnection, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
+	userID, err := parseID(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := pageSize(first)
+	if err != nil {
+		return nil, err
+	}
+	afterID, err := DecodeCursor("bookmark", after)
+	if err != nil {
+		return nil, err
+	}
+
+	// ブックマークは本人のみ閲覧可能
+	userUID, _ := GetUserUIDFromContext(ctx)
+	if userUID == "" || userUID != obj.UID {
+		return &model.BookmarkConnection{Edges: []*model.BookmarkEdge{}, PageInfo: &model.PageInfo{}}, nil
+	}
+
+	// Usecaseの呼び出し(次ページの有無を判定するため1件多く取得)
+	bookmarks, totalCount, err := r.BookmarkUsecase.ListBookmarks(ctx, usecase.ListBookmarksInput{
+		UserID:  userID,
+		AfterID: afterID,
+		Limit:   limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// モデル変換
+	hasNextPage := len(bookmarks) > limit
+	if hasNextPage {
+		bookmarks = bookmarks[:limit]
+	}
+	edges := make([]*model.BookmarkEdge, 0, len(bookmarks))
+	for _, bookmark := range bookmarks {
+		edges = append(edges, &model.BookmarkEdge{
+			Cursor:       EncodeCursor("bookmark", bookmark.ID),
+			BookmarkedAt: bookmark.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+			Node:         ToModelArticle(bookmark.Article),
+		})
+	}
+	pageInfo := &model.PageInfo{HasNextPage: hasNextPage}
+	if len(edges) > 0 {
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &model.BookmarkConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: int32(totalCount),
+	}, nil
+}
+
+// ========================
+// Mutation
+// ========================
+
+func (r *mutationResolver) ToggleBookmark(ctx context.Context, input model.ToggleBookmarkInput) (*model.ToggleBookmarkPayload, error) {
+	// ログインユーザーの取得
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し
+	articleID, err := parseID(input.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	article, bookmarked, err := r.BookmarkUsecase.ToggleBookmark(ctx, articleID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ToggleBookmarkPayload{
+		Article:    ToModelArticle(*article),
+		Bookmarked: bookmarked,
+	}, nil
+}
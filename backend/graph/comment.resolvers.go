@@ -0,0 +1,243 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	model "elasticsearch-sample/backend/graph/model"
+	entity "elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/usecase"
+)
+
+func (r *Resolver) Comment() CommentResolver { return &commentResolver{r} }
+
+type commentResolver struct{ *Resolver }
+
+// ========================
+// 汎用関数
+// ========================
+func ToModelComment(comment entity.Comment) *model.Comment {
+	result := &model.Comment{
+		ID:        fmt.Sprintf("%d", comment.ID),
+		ArticleID: fmt.Sprintf("%d", comment.ArticleID),
+		Body:      comment.Body,
+		Status:    model.CommentStatus(strings.ToUpper(comment.Status)),
+		CreatedAt: comment.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UpdatedAt: comment.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		UserID:    fmt.Sprintf("%d", comment.UserID),
+	}
+	if comment.ParentID != nil {
+		parentID := fmt.Sprintf("%d", *comment.ParentID)
+		result.ParentID = &parentID
+	}
+	return result
+}
+
+// listComments 記事のコメント(parentIDを指定した場合はその返信)をConnectionとして取得する
+func (r *Resolver) listComments(ctx context.Context, articleID uint, parentID *uint, first *int32, after *string) (*model.CommentConnection, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
+	limit, err := pageSize(first)
+	if err != nil {
+		return nil, err
+	}
+	afterID, err := DecodeCursor("comment", after)
+	if err != nil {
+		return nil, err
+	}
+
+	// 承認待ち・非表示のコメントを閲覧できるか判定するため、ログインユーザーを取得
+	var viewerID uint
+	if userUID, ok := GetUserUIDFromContext(ctx); ok {
+		user, err := r.UserUsecase.GetUserByUID(ctx, userUID)
+		if err != nil {
+			return nil, err
+		}
+		viewerID = user.ID
+	}
+
+	// Usecaseの呼び出し(次ページの有無を判定するため1件多く取得)
+	comments, totalCount, err := r.CommentUsecase.ListComments(ctx, usecase.ListCommentsInput{
+		ArticleID: articleID,
+		ParentID:  parentID,
+		ViewerID:  viewerID,
+		AfterID:   afterID,
+		Limit:     limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// モデル変換
+	hasNextPage := len(comments) > limit
+	if hasNextPage {
+		comments = comments[:limit]
+	}
+	edges := make([]*model.CommentEdge, 0, len(comments))
+	for _, comment := range comments {
+		edges = append(edges, &model.CommentEdge{
+			Cursor: EncodeCursor("comment", comment.ID),
+			Node:   ToModelComment(*comment),
+		})
+	}
+	pageInfo := &model.PageInfo{HasNextPage: hasNextPage}
+	if len(edges) > 0 {
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &model.CommentConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: int32(totalCount),
+	}, nil
+}
+
+// =======================
+// Resolver
+// ========================
+
+func (r *articleResolver) Comments(ctx context.Context, obj *model.Article, first *int32, after *string) (*model.CommentConnection, error) {
+	articleID, err := parseID(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.listComments(ctx, articleID, nil, first, after)
+}
+
+func (r *commentResolver) Author(ctx context.Context, obj *model.Comment) (*model.User, error) {
+	userID, err := parseID(obj.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	// DataLoaderで同一リクエスト内のユーザー取得をまとめる
+	user, err := r.loaders(ctx).UserByID.Load(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelUser(*user), nil
+}
+
+func (r *commentResolver) Replies(ctx context.Context, obj *model.Comment, first *int32, after *string) (*model.CommentConnection, error) {
+	articleID, err := parseID(obj.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	commentID, err := parseID(obj.ID)
+	if err != nil {
+		return nil, err
+	}
+	return r.listComments(ctx, articleID, &commentID, first, after)
+}
+
+// ========================
+// Mutation
+// ========================
+
+func (r *mutationResolver) AddComment(ctx context.Context, input model.AddCommentInput) (*model.Comment, error) {
+	// ログインユーザーの取得
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+
+	articleID, err := parseID(input.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	addInput := usecase.AddCommentInput{
+		ArticleID: articleID,
+		UserID:    user.ID,
+		Body:      input.Body,
+	}
+	if input.ParentID != nil {
+		parentID, err := parseID(*input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		addInput.ParentID = &parentID
+	}
+
+	// Usecaseの呼び出し
+	comment, err := r.CommentUsecase.AddComment(ctx, addInput)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelComment(*comment), nil
+}
+
+func (r *mutationResolver) EditComment(ctx context.Context, input model.EditCommentInput) (*model.Comment, error) {
+	// ログインユーザーの取得
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し(投稿者以外はUsecaseでエラーになる)
+	commentID, err := parseID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := r.CommentUsecase.EditComment(ctx, commentID, user.ID, input.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelComment(*comment), nil
+}
+
+func (r *mutationResolver) DeleteComment(ctx context.Context, input model.DeleteCommentInput) (bool, error) {
+	// ログインユーザーの取得
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return false, err
+	}
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return false, err
+	}
+
+	// Usecaseの呼び出し(投稿者・記事の作成者以外はUsecaseでエラーになる)
+	commentID, err := parseID(input.ID)
+	if err != nil {
+		return false, err
+	}
+	if err := r.CommentUsecase.DeleteComment(ctx, commentID, user.ID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+func (r *mutationResolver) ModerateComment(ctx context.Context, input model.ModerateCommentInput) (*model.Comment, error) {
+	// ログインユーザーの取得
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し(記事の作成者以外はUsecaseでエラーになる)
+	commentID, err := parseID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := r.CommentUsecase.ModerateComment(ctx, commentID, user.ID, strings.ToLower(string(input.Status)))
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelComment(*comment), nil
+}
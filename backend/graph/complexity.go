@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/complexity"
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"elasticsearch-sample/backend/graph/model"
+)
+
+const (
+	errCodeComplexityLimit = "COMPLEXITY_LIMIT_EXCEEDED"
+	errCodeDepthLimit      = "DEPTH_LIMIT_EXCEEDED"
+)
+
+const (
+	searchFieldCost = 50 // 検索エンジンへの問い合わせ1回分のコスト
+	listFieldCost   = 10 // DBから一覧を取得する1回分のコスト
+
+	// 件数を指定できない一覧フィールドで想定する件数
+	unboundedListSize = defaultPageSize
+)
+
+// NewComplexityRoot フィールドごとのコストを定義する
+// 一覧フィールドは子フィールドのコストに想定件数を掛けて計算する
+func NewComplexityRoot() ComplexityRoot {
+	var c ComplexityRoot
+
+	c.Query.Articles = func(childComplexity int) int {
+		return listFieldCost + childComplexity*maxArticlesListSize
+	}
+	c.Query.SearchArticles = func(childComplexity int, query string, tags []string) int {
+		return searchFieldCost + childComplexity*unboundedListSize
+	}
+	c.Query.PopularTags = func(childComplexity int, first *int32) int {
+		size := defaultPopularTags
+		if first != nil && *first >= 0 {
+			size = int(*first)
+		}
+		return searchFieldCost + childComplexity*size
+	}
+	c.Query.DeletedArticles = func(childComplexity int, first *int32, after *string) int {
+		return listFieldCost + childComplexity*connectionSize(first)
+	}
+	c.Query.APIKeys = func(childComplexity int) int {
+		return listFieldCost + childComplexity*unboundedListSize
+	}
+	c.User.Articles = func(childComplexity int, first *int32, after *string, status *model.ArticleStatus) int {
+		return listFieldCost + childComplexity*connectionSize(first)
+	}
+	c.User.Bookmarks = func(childComplexity int, first *int32, after *string) int {
+		return listFieldCost + childComplexity*connectionSize(first)
+	}
+	c.Article.Revisions = func(childComplexity int, first *int32, after *string) int {
+		return listFieldCost + childComplexity*connectionSize(first)
+	}
+	c.Article.Comments = func(childComplexity int, first *int32, after *string) int {
+		return listFieldCost + childComplexity*connectionSize(first)
+	}
+	c.Article.Attachments = func(childComplexity int) int {
+		return listFieldCost + childComplexity*unboundedListSize
+	}
+	c.Comment.Replies = func(childComplexity int, first *int32, after *string) int {
+		return listFieldCost + childComplexity*connectionSize(first)
+	}
+
+	return c
+}
+
+// connectionSize Connectionのfirstで想定する取得件数
+func connectionSize(first *int32) int {
+	if first == nil || *first < 0 {
+		return defaultPageSize
+	}
+	// 上限を超える値はリゾルバーでエラーになるため上限で計算する
+	return min(int(*first), maxPageSize)
+}
+
+// QueryLimitConfig 1回の操作で許可するコストとネストの深さ(0の場合は制限なし)
+type QueryLimitConfig struct {
+	MaxComplexity int
+	MaxDepth      int
+}
+
+// QueryLimit コストやネストの深さが上限を超える操作を実行前に拒否する
+type QueryLimit struct {
+	config QueryLimitConfig
+	es     graphql.ExecutableSchema
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationContextMutator
+} = &QueryLimit{}
+
+func NewQueryLimit(config QueryLimitConfig) *QueryLimit {
+	return &QueryLimit{config: config}
+}
+
+func (l *QueryLimit) ExtensionName() string {
+	return "QueryLimit"
+}
+
+func (l *QueryLimit) Validate(schema graphql.ExecutableSchema) error {
+	l.es = schema
+	return nil
+}
+
+// MutateOperationContext 操作のネストの深さとコストを計算し、上限を超えていればエラーを返す
+func (l *QueryLimit) MutateOperationContext(ctx context.Context, opCtx *graphql.OperationContext) *gqlerror.Error {
+	op := opCtx.Doc.Operations.ForName(opCtx.OperationName)
+	if op == nil {
+		return nil
+	}
+
+	if l.config.MaxDepth > 0 {
+		if depth := selectionDepth(op.SelectionSet, opCtx.Doc); depth > l.config.MaxDepth {
+			return &gqlerror.Error{
+				Message: fmt.Sprintf("operation has depth %d, which exceeds the limit of %d", depth, l.config.MaxDepth),
+				Extensions: map[string]any{
+					"code":     errCodeDepthLimit,
+					"depth":    depth,
+					"maxDepth": l.config.MaxDepth,
+				},
+			}
+		}
+	}
+
+	if l.config.MaxComplexity > 0 {
+		cost := complexity.Calculate(ctx, l.es, op, opCtx.Variables)
+		if cost > l.config.MaxComplexity {
+			return &gqlerror.Error{
+				Message: fmt.Sprintf("operation has complexity %d, which exceeds the limit of %d", cost, l.config.MaxComplexity),
+				Extensions: map[string]any{
+					"code":          errCodeComplexityLimit,
+					"complexity":    cost,
+					"maxComplexity": l.config.MaxComplexity,
+				},
+			}
+		}
+	}
+
+	return nil
+}
+
+// selectionDepth 選択セット(フラグメントを含む)のフィールドのネストの深さ
+// イントロスペクション(__schemaなど)は開発ツールが深くネストするため対象外
+func selectionDepth(selections ast.SelectionSet, doc *ast.QueryDocument) int {
+	depth := 0
+	for _, selection := range selections {
+		var d int
+		switch s := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(s.Name, "__") {
+				continue
+			}
+			d = 1 + selectionDepth(s.SelectionSet, doc)
+		case *ast.InlineFragment:
+			d = selectionDepth(s.SelectionSet, doc)
+		case *ast.FragmentSpread:
+			if fragment := doc.Fragments.ForName(s.Name); fragment != nil {
+				d = selectionDepth(fragment.SelectionSet, doc)
+			}
+		}
+		depth = max(depth, d)
+	}
+	return depth
+}
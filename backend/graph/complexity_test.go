@@ -0,0 +1,30 @@
+package graph
+
+import (
+	"testing"
+
+	entity "elasticsearch-sample/backend/internal/domain/model"
+)
+
+func TestArticlesIsBoundedByComplexitySize(t *testing.T) {
+	resolver, articles := newTestResolver()
+	for i := range maxArticlesListSize * 2 {
+		article := &entity.Article{UserID: 1, Status: "published"}
+		article.ID = uint(i + 2)
+		articles.articles = append(articles.articles, article)
+	}
+
+	got, err := (&queryResolver{resolver}).Articles(withUser("author"))
+	if err != nil {
+		t.Fatalf("Articles() error = %v", err)
+	}
+	// コストの計算で想定した件数を超えて読み込まない
+	if len(got) != maxArticlesListSize || len(articles.listed) != 1 {
+		t.Errorf("Articles() returned %d articles in %d queries, want %d in 1", len(got), len(articles.listed), maxArticlesListSize)
+	}
+
+	c := NewComplexityRoot()
+	if cost, want := c.Query.Articles(1), listFieldCost+len(got); cost != want {
+		t.Errorf("Query.Articles complexity = %d, want %d", cost, want)
+	}
+}
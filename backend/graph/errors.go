@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"strconv"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"gorm.io/gorm"
+
+	"elasticsearch-sample/backend/internal/dataloader"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/logging"
+)
+
+// ErrorPresenter Resolverのエラーをextensions.code付きのGraphQLエラーに変換する
+// 内部エラーの詳細はログにのみ出力し、クライアントには返さない
+func ErrorPresenter(ctx context.Context, err error) *gqlerror.Error {
+	// クエリの構文エラーなど、既にGraphQLエラーになっているものはそのまま返す
+	var gqlErr *gqlerror.Error
+	if errors.As(err, &gqlErr) && gqlErr.Unwrap() == nil {
+		return gqlErr
+	}
+
+	appErr := toAppError(err)
+	switch appErr.Code {
+	case apperror.CodeInternal, apperror.CodeUnavailable:
+		logging.FromContext(ctx).ErrorContext(ctx, "❌ "+string(appErr.Code), "error", err)
+	}
+
+	presented := gqlerror.WrapPath(graphql.GetPath(ctx), err)
+	presented.Message = appErr.Message
+	presented.Extensions = map[string]any{
+		"code": appErr.Code,
+	}
+	return presented
+}
+
+// RecoverFunc Resolver内のpanicを内部エラーとして扱う(スタックトレースはログにのみ出力)
+func RecoverFunc(ctx context.Context, err any) error {
+	logging.FromContext(ctx).ErrorContext(ctx, "❌ panic", "error", err, "stack", string(debug.Stack()))
+	return apperror.Internal(fmt.Errorf("panic: %v", err))
+}
+
+// toAppError エラーをコード付きのドメインエラーに変換する
+func toAppError(err error) *apperror.Error {
+	var appErr *apperror.Error
+	switch {
+	case errors.As(err, &appErr):
+		return appErr
+	case errors.Is(err, gorm.ErrRecordNotFound), errors.Is(err, dataloader.ErrNotFound):
+		return apperror.NotFound("resource not found")
+	case errors.Is(err, gorm.ErrDuplicatedKey):
+		return apperror.Conflict("resource already exists")
+	case errors.Is(err, context.DeadlineExceeded):
+		return apperror.Unavailable("request timed out", err)
+	default:
+		return apperror.Internal(err)
+	}
+}
+
+// parseID GraphQLのIDを数値IDに変換する
+func parseID(id string) (uint, error) {
+	parsed, err := strconv.ParseUint(id, 10, 32)
+	if err != nil {
+		return 0, apperror.Validation(fmt.Sprintf("invalid id: %q", id))
+	}
+	return uint(parsed), nil
+}
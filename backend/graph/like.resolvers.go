@@ -0,0 +1,55 @@
+package graph
+
+import (
+	"context"
+
+	model "elasticsearch-sample/backend/graph/model"
+	entity "elasticsearch-sample/backend/internal/domain/model"
+)
+
+// =======================
+// Resolver
+// ========================
+
+func (r *articleResolver) ViewerHasLiked(ctx context.Context, obj *model.Article) (bool, error) {
+	if _, ok := GetUserUIDFromContext(ctx); !ok {
+		return false, nil
+	}
+	articleID, err := parseID(obj.ID)
+	if err != nil {
+		return false, err
+	}
+
+	// DataLoaderで同一リクエスト内の記事をまとめて確認する
+	return r.loaders(ctx).LikedByViewer.Load(ctx, articleID)
+}
+
+// ========================
+// Mutation
+// ========================
+
+func (r *mutationResolver) ToggleArticleLike(ctx context.Context, input model.ToggleArticleLikeInput) (*model.ToggleArticleLikePayload, error) {
+	// ログインユーザーの取得
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し
+	articleID, err := parseID(input.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	article, liked, err := r.LikeUsecase.ToggleLike(ctx, articleID, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &model.ToggleArticleLikePayload{
+		Article: ToModelArticle(*article),
+		Liked:   liked,
+	}, nil
+}
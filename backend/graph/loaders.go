@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"context"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+
+	"elasticsearch-sample/backend/internal/dataloader"
+	entity "elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/usecase"
+)
+
+const loadersKey contextKey = "loaders"
+
+const (
+	loaderWait     = 2 * time.Millisecond // バッチにまとめるための待ち時間
+	loaderMaxBatch = 100                  // 1回のクエリで取得するキー数の上限
+)
+
+// Loaders 1回のレスポンスの中で共有するDataLoaderの集合
+type Loaders struct {
+	UserByID *dataloader.Loader[uint, *entity.User]
+	// 記事IDごとに、ログインユーザーがいいねしているか(未ログインの場合はすべてfalse)
+	LikedByViewer *dataloader.Loader[uint, bool]
+}
+
+func NewLoaders(userUsecase usecase.UserUsecase, likeUsecase usecase.LikeUsecase) *Loaders {
+	return &Loaders{
+		UserByID: dataloader.NewLoader(func(ctx context.Context, ids []uint) (map[uint]*entity.User, error) {
+			users, err := userUsecase.GetUsersByIDs(ctx, ids)
+			if err != nil {
+				return nil, err
+			}
+			result := make(map[uint]*entity.User, len(users))
+			for _, user := range users {
+				result[user.ID] = user
+			}
+			return result, nil
+		}, loaderWait, loaderMaxBatch),
+		LikedByViewer: dataloader.NewLoader(func(ctx context.Context, articleIDs []uint) (map[uint]bool, error) {
+			result := make(map[uint]bool, len(articleIDs))
+			for _, articleID := range articleIDs {
+				result[articleID] = false
+			}
+			userUID, ok := GetUserUIDFromContext(ctx)
+			if !ok {
+				return result, nil
+			}
+			user, err := userUsecase.GetUserByUID(ctx, userUID)
+			if err != nil {
+				return nil, err
+			}
+			likedIDs, err := likeUsecase.LikedArticleIDs(ctx, user.ID, articleIDs)
+			if err != nil {
+				return nil, err
+			}
+			for _, articleID := range likedIDs {
+				result[articleID] = true
+			}
+			return result, nil
+		}, loaderWait, loaderMaxBatch),
+	}
+}
+
+// DataLoaders レスポンスごとにDataLoaderを生成してContextに入れるgqlgenの拡張
+// HTTPのミドルウェアで生成するとWebSocketでは接続中ずっと同じキャッシュが使われるため、
+// Subscriptionではイベントごとに生成し直す
+type DataLoaders struct {
+	userUsecase usecase.UserUsecase
+	likeUsecase usecase.LikeUsecase
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = &DataLoaders{}
+
+func NewDataLoaders(userUsecase usecase.UserUsecase, likeUsecase usecase.LikeUsecase) *DataLoaders {
+	return &DataLoaders{userUsecase: userUsecase, likeUsecase: likeUsecase}
+}
+
+func (d *DataLoaders) ExtensionName() string {
+	return "DataLoaders"
+}
+
+func (d *DataLoaders) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+func (d *DataLoaders) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	return next(context.WithValue(ctx, loadersKey, NewLoaders(d.userUsecase, d.likeUsecase)))
+}
+
+// LoadersFromContext ContextからDataLoaderを取り出す
+func LoadersFromContext(ctx context.Context) (*Loaders, bool) {
+	loaders, ok := ctx.Value(loadersKey).(*Loaders)
+	return loaders, ok
+}
+
+// loaders ResolverでDataLoaderを取得する
+// DataLoadersの拡張を通らずに呼ばれた場合は、その場でキャッシュを共有しないローダーを生成する
+func (r *Resolver) loaders(ctx context.Context) *Loaders {
+	if loaders, ok := LoadersFromContext(ctx); ok {
+		return loaders
+	}
+	return NewLoaders(r.UserUsecase, r.LikeUsecase)
+}
@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/vektah/gqlparser/v2/ast"
+
+	"elasticsearch-sample/backend/internal/metrics"
+)
+
+var (
+	operationDuration = metrics.Factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "graphql_operation_duration_seconds",
+		Help:    "GraphQL操作の実行にかかった時間",
+		Buckets: metrics.LatencyBuckets,
+	}, []string{"operation", "type"})
+
+	operationErrors = metrics.Factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "graphql_errors_total",
+		Help: "GraphQLのレスポンスに含まれたエラー数",
+	}, []string{"operation", "type", "code"})
+)
+
+// 許可リストにない操作名をまとめるラベル
+const otherOperation = "other"
+
+// Metrics 操作名ごとのレイテンシとエラー数を記録するgqlgenの拡張
+// 操作名はクライアントが自由に付けられるため、ラベルの種類が増え続けないよう許可リストの操作名だけを記録する
+type Metrics struct {
+	operations map[string]bool
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+} = &Metrics{}
+
+func NewMetrics(operations []string) *Metrics {
+	m := &Metrics{operations: make(map[string]bool, len(operations))}
+	for _, name := range operations {
+		m.operations[name] = true
+	}
+	return m
+}
+
+func (m *Metrics) ExtensionName() string {
+	return "Metrics"
+}
+
+func (m *Metrics) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse 操作の開始(リクエストの解析開始)からレスポンスまでの時間を記録する
+func (m *Metrics) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	resp := next(ctx)
+	if !graphql.HasOperationContext(ctx) {
+		return resp
+	}
+
+	opCtx := graphql.GetOperationContext(ctx)
+	operationName := m.operationLabel(opCtx.Operation)
+	operationType := "unknown"
+	if opCtx.Operation != nil {
+		operationType = string(opCtx.Operation.Operation)
+	}
+
+	// Subscriptionはレスポンスごとに呼ばれるため、レイテンシは記録しない
+	if operationType != string(ast.Subscription) {
+		operationDuration.WithLabelValues(operationName, operationType).Observe(time.Since(opCtx.Stats.OperationStart).Seconds())
+	}
+
+	if resp != nil {
+		for _, err := range resp.Errors {
+			code := "UNKNOWN"
+			if c, ok := err.Extensions["code"]; ok {
+				code = fmt.Sprint(c)
+			}
+			operationErrors.WithLabelValues(operationName, operationType, code).Inc()
+		}
+	}
+
+	return resp
+}
+
+// operationLabel 実行した操作の名前をラベルに使える値にする
+// 文書の解析に失敗した場合や、許可リストにない名前はotherにまとめる
+func (m *Metrics) operationLabel(op *ast.OperationDefinition) string {
+	switch {
+	case op == nil:
+		return otherOperation
+	case op.Name == "":
+		return "anonymous"
+	case m.operations[op.Name]:
+		return op.Name
+	default:
+		return otherOperation
+	}
+}
@@ -0,0 +1,29 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+func TestMetricsOperationLabel(t *testing.T) {
+	m := NewMetrics([]string{"SearchArticles"})
+
+	tests := []struct {
+		name string
+		op   *ast.OperationDefinition
+		want string
+	}{
+		{"allowed operation", &ast.OperationDefinition{Name: "SearchArticles"}, "SearchArticles"},
+		{"unknown operation", &ast.OperationDefinition{Name: "Random12345"}, otherOperation},
+		{"anonymous operation", &ast.OperationDefinition{}, "anonymous"},
+		{"unparsed document", nil, otherOperation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := m.operationLabel(tt.op); got != tt.want {
+				t.Errorf("operationLabel() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
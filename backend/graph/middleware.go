@@ -2,18 +2,58 @@ package graph
 
 import (
 	"context"
+	"encoding/json"
+	"fmt"
+	"mime"
 	"net/http"
 	"strings"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/infrastructure/auth"
+	"elasticsearch-sample/backend/internal/logging"
+	"elasticsearch-sample/backend/internal/usecase"
 )
 
 type contextKey string
 
-const userIDKey contextKey = "userID"
+const (
+	userIDKey       contextKey = "userID"
+	apiKeyScopesKey contextKey = "apiKeyScopes"
+)
+
+// TokenVerifier 署名付きトークンを検証してクレームを返す
+type TokenVerifier interface {
+	Verify(ctx context.Context, token string) (*auth.Claims, error)
+}
 
-// Middleware HTTPリクエストからUserIDを抽出してContextに入れる
-func AuthMiddleware() func(http.Handler) http.Handler {
+// Middleware HTTPリクエストのトークン(またはAPIキー)を検証し、UserIDを抽出してContextに入れる
+// 初回ログイン時はユーザーを作成する
+func AuthMiddleware(verifier TokenVerifier, userUsecase usecase.UserUsecase, apiKeyUsecase usecase.APIKeyUsecase) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			logger := logging.FromContext(r.Context())
+
+			// APIキーの場合はキーの所有者として扱い、スコープをContextに入れる
+			if rawKey := r.Header.Get("X-API-Key"); rawKey != "" {
+				apiKey, err := apiKeyUsecase.Authenticate(r.Context(), rawKey)
+				if err != nil {
+					if apperror.Is(err, apperror.CodeUnauthenticated) {
+						writeError(w, http.StatusUnauthorized, apperror.CodeUnauthenticated, "invalid api key")
+						return
+					}
+					logger.ErrorContext(r.Context(), "❌ APIキーの検証に失敗しました", "error", err)
+					writeError(w, http.StatusInternalServerError, apperror.CodeInternal, "internal server error")
+					return
+				}
+
+				ctx := context.WithValue(r.Context(), userIDKey, apiKey.Owner.UID)
+				ctx = context.WithValue(ctx, apiKeyScopesKey, apiKey.Scopes)
+				next.ServeHTTP(w, r.WithContext(ctx))
+				return
+			}
+
 			authHeader := r.Header.Get("Authorization")
 
 			// ヘッダーがない場合はそのまま次へ（未ログイン状態）
@@ -22,23 +62,146 @@ func AuthMiddleware() func(http.Handler) http.Handler {
 				return
 			}
 
-			// "Bearer admin123" の "admin123" の部分を取得
-			parts := strings.Split(authHeader, " ")
-			if len(parts) == 2 && parts[0] == "Bearer" {
-				uid := parts[1]
-				// Contextに値をセット
-				ctx := context.WithValue(r.Context(), userIDKey, uid)
-				next.ServeHTTP(w, r.WithContext(ctx))
+			// 不正なトークンは未ログインとして扱わず、401を返す
+			claims, err := authenticate(r.Context(), verifier, authHeader)
+			if err != nil {
+				logger.WarnContext(r.Context(), "⚠️ 認証に失敗しました", "error", err)
+				writeError(w, http.StatusUnauthorized, apperror.CodeUnauthenticated, "invalid token")
 				return
 			}
 
+			uid, err := provisionUser(r.Context(), userUsecase, claims)
+			if err != nil {
+				logger.ErrorContext(r.Context(), "❌ ユーザーの作成に失敗しました", "error", err)
+				writeError(w, http.StatusInternalServerError, apperror.CodeInternal, "internal server error")
+				return
+			}
+
+			// Contextに値をセット
+			ctx := context.WithValue(r.Context(), userIDKey, uid)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// WebsocketInitFunc WebSocket接続時のペイロードのトークンを検証し、UserIDを抽出してContextに入れる
+// ブラウザのWebSocketはヘッダーを指定できないため、connection_initのAuthorizationを使う
+func WebsocketInitFunc(verifier TokenVerifier, userUsecase usecase.UserUsecase) transport.WebsocketInitFunc {
+	return func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		authHeader := initPayload.Authorization()
+		if authHeader == "" {
+			return ctx, &initPayload, nil
+		}
+
+		claims, err := authenticate(ctx, verifier, authHeader)
+		if err != nil {
+			logging.FromContext(ctx).WarnContext(ctx, "⚠️ 認証に失敗しました", "error", err)
+			return ctx, nil, fmt.Errorf("invalid token")
+		}
+
+		uid, err := provisionUser(ctx, userUsecase, claims)
+		if err != nil {
+			logging.FromContext(ctx).ErrorContext(ctx, "❌ ユーザーの作成に失敗しました", "error", err)
+			return ctx, nil, fmt.Errorf("internal server error")
+		}
+		return context.WithValue(ctx, userIDKey, uid), &initPayload, nil
+	}
+}
+
+// WebsocketCheckOrigin WebSocket接続のOriginがCORSで許可したオリジンに含まれるか確認する
+// WebSocketはCORSの対象外のため、他のサイトのページから接続されないよう同じ設定で制限する
+// ("*"はすべてのオリジン、"https://*.example.com"のように1つの"*"を含むものはパターンとして扱う)
+func WebsocketCheckOrigin(allowedOrigins []string) func(r *http.Request) bool {
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			// ブラウザ以外のクライアントはOriginを送らない
+			return true
+		}
+		origin = strings.ToLower(origin)
+		for _, allowed := range allowedOrigins {
+			allowed = strings.ToLower(allowed)
+			if allowed == "*" || allowed == origin {
+				return true
+			}
+			if prefix, suffix, ok := strings.Cut(allowed, "*"); ok &&
+				len(origin) >= len(prefix)+len(suffix) &&
+				strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// authenticate "Bearer <token>" のトークンを検証し、クレームを返す
+func authenticate(ctx context.Context, verifier TokenVerifier, authHeader string) (*auth.Claims, error) {
+	token, ok := strings.CutPrefix(authHeader, "Bearer ")
+	if !ok || token == "" {
+		return nil, fmt.Errorf("authorization header must be Bearer token")
+	}
+
+	return verifier.Verify(ctx, token)
+}
+
+// provisionUser トークンのsubjectに対応するユーザーを作成・更新し、UIDを返す
+func provisionUser(ctx context.Context, userUsecase usecase.UserUsecase, claims *auth.Claims) (string, error) {
+	user, err := userUsecase.ProvisionUser(ctx, usecase.ProvisionUserInput{
+		UID:         claims.Subject,
+		DisplayName: claims.Name,
+		Email:       claims.Email,
+	})
+	if err != nil {
+		return "", err
+	}
+	return user.UID, nil
+}
+
+// BodyLimitMiddleware リクエストボディのサイズを制限する
+// ファイルのアップロード(multipart/form-data)はmaxUploadBytesの分だけ上限を広げる
+// Content-Lengthで上限を超えるとわかる場合は、読み込まずに413を返す
+func BodyLimitMiddleware(maxBytes int64, maxUploadBytes int64) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			maxBytes := maxBytes
+			if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "multipart/form-data" {
+				maxBytes += maxUploadBytes
+			}
+			if r.ContentLength > maxBytes {
+				writeError(w, http.StatusRequestEntityTooLarge, apperror.CodeValidation, "request body too large")
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
 			next.ServeHTTP(w, r)
 		})
 	}
 }
 
+// writeError GraphQLのエラー形式でエラーレスポンスを返す
+func writeError(w http.ResponseWriter, status int, code apperror.Code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	if status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+	}
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"errors": []map[string]any{
+			{
+				"message":    message,
+				"extensions": map[string]any{"code": code},
+			},
+		},
+	})
+}
+
 // GetUserUIDFromContext ResolverなどでContextからIDを取り出すためのヘルパー
 func GetUserUIDFromContext(ctx context.Context) (string, bool) {
 	uid, ok := ctx.Value(userIDKey).(string)
 	return uid, ok
 }
+
+// GetAPIKeyScopesFromContext APIキーで認証された場合に、キーのスコープを取り出すためのヘルパー
+func GetAPIKeyScopesFromContext(ctx context.Context) ([]string, bool) {
+	scopes, ok := ctx.Value(apiKeyScopesKey).([]string)
+	return scopes, ok
+}
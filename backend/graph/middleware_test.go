@@ -0,0 +1,36 @@
+package graph
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebsocketCheckOrigin(t *testing.T) {
+	tests := []struct {
+		name    string
+		allowed []string
+		origin  string
+		want    bool
+	}{
+		{"no origin header", []string{"https://app.example.com"}, "", true},
+		{"allowed origin", []string{"https://app.example.com"}, "https://app.example.com", true},
+		{"case insensitive", []string{"https://App.example.com"}, "https://app.EXAMPLE.com", true},
+		{"other site", []string{"https://app.example.com"}, "https://evil.example.net", false},
+		{"different scheme", []string{"https://app.example.com"}, "http://app.example.com", false},
+		{"wildcard", []string{"*"}, "https://evil.example.net", true},
+		{"subdomain pattern", []string{"https://*.example.com"}, "https://app.example.com", true},
+		{"pattern suffix mismatch", []string{"https://*.example.com"}, "https://example.com.evil.net", false},
+		{"empty list", nil, "https://app.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/query", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := WebsocketCheckOrigin(tt.allowed)(r); got != tt.want {
+				t.Errorf("WebsocketCheckOrigin(%v)(%q) = %v, want %v", tt.allowed, tt.origin, got, tt.want)
+			}
+		})
+	}
+}
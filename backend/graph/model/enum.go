@@ -7,3 +7,34 @@ const (
 	ArticleStatusPublished ArticleStatus = "PUBLISHED"
 	ArticleStatusArchived  ArticleStatus = "ARCHIVED"
 )
+
+type APIKeyScope string
+
+const (
+	APIKeyScopeArticlesRead  APIKeyScope = "ARTICLES_READ"
+	APIKeyScopeArticlesWrite APIKeyScope = "ARTICLES_WRITE"
+	APIKeyScopeSearchAdmin   APIKeyScope = "SEARCH_ADMIN"
+)
+
+type DiffMode string
+
+const (
+	DiffModeLine DiffMode = "LINE"
+	DiffModeWord DiffMode = "WORD"
+)
+
+type DiffOperation string
+
+const (
+	DiffOperationEqual  DiffOperation = "EQUAL"
+	DiffOperationInsert DiffOperation = "INSERT"
+	DiffOperationDelete DiffOperation = "DELETE"
+)
+
+type CommentStatus string
+
+const (
+	CommentStatusPending  CommentStatus = "PENDING"
+	CommentStatusApproved CommentStatus = "APPROVED"
+	CommentStatusHidden   CommentStatus = "HIDDEN"
+)
@@ -2,37 +2,265 @@
 
 package model
 
+import (
+	"github.com/99designs/gqlgen/graphql"
+)
+
+type APIKey struct {
+	ID         string        `json:"id"`
+	Name       string        `json:"name"`
+	Prefix     string        `json:"prefix"`
+	Scopes     []APIKeyScope `json:"scopes"`
+	ExpiresAt  *string       `json:"expiresAt,omitempty"`
+	LastUsedAt *string       `json:"lastUsedAt,omitempty"`
+	RevokedAt  *string       `json:"revokedAt,omitempty"`
+	CreatedAt  string        `json:"createdAt"`
+	UserID     string        `json:"userID"`
+	Owner      *User         `json:"owner"`
+}
+
+type AddCommentInput struct {
+	ArticleID string  `json:"articleID"`
+	ParentID  *string `json:"parentID,omitempty"`
+	Body      string  `json:"body"`
+}
+
 type ArchiveArticleInput struct {
 	ID string `json:"id"`
 }
 
 type Article struct {
+	ID             string                     `json:"id"`
+	Title          string                     `json:"title"`
+	Slug           string                     `json:"slug"`
+	Content        *string                    `json:"content,omitempty"`
+	ContentHTML    string                     `json:"contentHTML"`
+	Excerpt        string                     `json:"excerpt"`
+	ReadingMinutes int32                      `json:"readingMinutes"`
+	Status         ArticleStatus              `json:"status"`
+	CreatedAt      string                     `json:"createdAt"`
+	UpdatedAt      string                     `json:"updatedAt"`
+	PublishAt      *string                    `json:"publishAt,omitempty"`
+	DeletedAt      *string                    `json:"deletedAt,omitempty"`
+	Tags           []string                   `json:"tags"`
+	LikeCount      int32                      `json:"likeCount"`
+	ViewerHasLiked bool                       `json:"viewerHasLiked"`
+	UserID         string                     `json:"userID"`
+	Author         *User                      `json:"author"`
+	Revisions      *ArticleRevisionConnection `json:"revisions"`
+	Comments       *CommentConnection         `json:"comments"`
+	Attachments    []*Attachment              `json:"attachments"`
+}
+
+type ArticleConnection struct {
+	Edges      []*ArticleEdge `json:"edges"`
+	PageInfo   *PageInfo      `json:"pageInfo"`
+	TotalCount int32          `json:"totalCount"`
+}
+
+type ArticleEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Article `json:"node"`
+}
+
+type ArticleRevision struct {
 	ID        string        `json:"id"`
+	Version   int32         `json:"version"`
 	Title     string        `json:"title"`
 	Content   *string       `json:"content,omitempty"`
 	Status    ArticleStatus `json:"status"`
+	EditorID  *string       `json:"editorID,omitempty"`
+	Editor    *User         `json:"editor,omitempty"`
 	CreatedAt string        `json:"createdAt"`
-	UpdatedAt string        `json:"updatedAt"`
-	UserID    string        `json:"userID"`
-	Author    *User         `json:"author"`
+}
+
+type ArticleRevisionConnection struct {
+	Edges      []*ArticleRevisionEdge `json:"edges"`
+	PageInfo   *PageInfo              `json:"pageInfo"`
+	TotalCount int32                  `json:"totalCount"`
+}
+
+type ArticleRevisionEdge struct {
+	Cursor string           `json:"cursor"`
+	Node   *ArticleRevision `json:"node"`
+}
+
+type Attachment struct {
+	ID          string `json:"id"`
+	ArticleID   string `json:"articleID"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Size        int32  `json:"size"`
+	URL         string `json:"url"`
+	CreatedAt   string `json:"createdAt"`
+}
+
+type BookmarkConnection struct {
+	Edges      []*BookmarkEdge `json:"edges"`
+	PageInfo   *PageInfo       `json:"pageInfo"`
+	TotalCount int32           `json:"totalCount"`
+}
+
+type BookmarkEdge struct {
+	Cursor       string   `json:"cursor"`
+	BookmarkedAt string   `json:"bookmarkedAt"`
+	Node         *Article `json:"node"`
+}
+
+type Comment struct {
+	ID        string             `json:"id"`
+	ArticleID string             `json:"articleID"`
+	ParentID  *string            `json:"parentID,omitempty"`
+	Body      string             `json:"body"`
+	Status    CommentStatus      `json:"status"`
+	CreatedAt string             `json:"createdAt"`
+	UpdatedAt string             `json:"updatedAt"`
+	UserID    string             `json:"userID"`
+	Author    *User              `json:"author"`
+	Replies   *CommentConnection `json:"replies"`
+}
+
+type CommentConnection struct {
+	Edges      []*CommentEdge `json:"edges"`
+	PageInfo   *PageInfo      `json:"pageInfo"`
+	TotalCount int32          `json:"totalCount"`
+}
+
+type CommentEdge struct {
+	Cursor string   `json:"cursor"`
+	Node   *Comment `json:"node"`
+}
+
+type CreateAPIKeyInput struct {
+	Name      string        `json:"name"`
+	Scopes    []APIKeyScope `json:"scopes"`
+	ExpiresAt *string       `json:"expiresAt,omitempty"`
+}
+
+type CreateAPIKeyPayload struct {
+	APIKey *APIKey `json:"apiKey"`
+	Key    string  `json:"key"`
 }
 
 type CreateArticleInput struct {
-	Title   string  `json:"title"`
-	Content *string `json:"content,omitempty"`
+	Title   string   `json:"title"`
+	Content *string  `json:"content,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+type DeleteArticleInput struct {
+	ID string `json:"id"`
+}
+
+type DeleteAttachmentInput struct {
+	ID string `json:"id"`
+}
+
+type DeleteCommentInput struct {
+	ID string `json:"id"`
+}
+
+type DiffChunk struct {
+	Operation DiffOperation `json:"operation"`
+	Text      string        `json:"text"`
+}
+
+type EditCommentInput struct {
+	ID   string `json:"id"`
+	Body string `json:"body"`
+}
+
+type ModerateCommentInput struct {
+	ID     string        `json:"id"`
+	Status CommentStatus `json:"status"`
 }
 
 type Mutation struct {
 }
 
+type PageInfo struct {
+	HasNextPage bool    `json:"hasNextPage"`
+	EndCursor   *string `json:"endCursor,omitempty"`
+}
+
 type PublishArticleInput struct {
 	ID string `json:"id"`
 }
 
+type PurgeArticleInput struct {
+	ID string `json:"id"`
+}
+
 type Query struct {
 }
 
+type RestoreArticleInput struct {
+	ID string `json:"id"`
+}
+
+type RestoreRevisionInput struct {
+	ArticleID string `json:"articleID"`
+	Version   int32  `json:"version"`
+}
+
+type RevisionDiff struct {
+	From    *ArticleRevision `json:"from"`
+	To      *ArticleRevision `json:"to"`
+	Title   []*DiffChunk     `json:"title"`
+	Content []*DiffChunk     `json:"content"`
+}
+
+type RevokeAPIKeyInput struct {
+	ID string `json:"id"`
+}
+
+type ScheduleArticlePublicationInput struct {
+	ID        string `json:"id"`
+	PublishAt string `json:"publishAt"`
+}
+
+type Subscription struct {
+}
+
+type TagCount struct {
+	Name  string `json:"name"`
+	Count int32  `json:"count"`
+}
+
+type ToggleArticleLikeInput struct {
+	ArticleID string `json:"articleID"`
+}
+
+type ToggleArticleLikePayload struct {
+	Article *Article `json:"article"`
+	Liked   bool     `json:"liked"`
+}
+
+type ToggleBookmarkInput struct {
+	ArticleID string `json:"articleID"`
+}
+
+type ToggleBookmarkPayload struct {
+	Article    *Article `json:"article"`
+	Bookmarked bool     `json:"bookmarked"`
+}
+
+type UpdateArticleInput struct {
+	ID      string   `json:"id"`
+	Title   *string  `json:"title,omitempty"`
+	Content *string  `json:"content,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+type UploadAttachmentInput struct {
+	ArticleID string         `json:"articleID"`
+	File      graphql.Upload `json:"file"`
+}
+
 type User struct {
-	ID  string `json:"id"`
-	UID string `json:"uid"`
+	ID          string              `json:"id"`
+	UID         string              `json:"uid"`
+	DisplayName string              `json:"displayName"`
+	Articles    *ArticleConnection  `json:"articles"`
+	Bookmarks   *BookmarkConnection `json:"bookmarks"`
 }
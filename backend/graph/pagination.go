@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"elasticsearch-sample/backend/internal/domain/apperror"
+)
+
+const (
+	defaultPageSize = 20  // firstが未指定の場合の取得件数
+	maxPageSize     = 100 // firstで指定できる取得件数の上限
+
+	defaultPopularTags = 10 // popularTagsのfirstが未指定(null)の場合の取得件数
+
+	// ページングのないarticlesで返す件数の上限 (それ以降はUser.articlesなどのConnectionで取得する)
+	maxArticlesListSize = maxPageSize
+)
+
+// pageSize firstの値を取得件数に変換する
+func pageSize(first *int32) (int, error) {
+	if first == nil {
+		return defaultPageSize, nil
+	}
+	if *first < 0 || *first > maxPageSize {
+		return 0, apperror.Validation(fmt.Sprintf("first must be between 0 and %d", maxPageSize))
+	}
+	return int(*first), nil
+}
+
+// EncodeCursor IDを不透明なカーソル文字列に変換する
+func EncodeCursor(prefix string, id uint) string {
+	return base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf("%s:%d", prefix, id)))
+}
+
+// DecodeCursor カーソル文字列をIDに変換する(未指定の場合は0)
+func DecodeCursor(prefix string, cursor *string) (uint, error) {
+	if cursor == nil || *cursor == "" {
+		return 0, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(*cursor)
+	if err != nil {
+		return 0, apperror.Validation("invalid cursor")
+	}
+	value, ok := strings.CutPrefix(string(decoded), prefix+":")
+	if !ok {
+		return 0, apperror.Validation("invalid cursor")
+	}
+	id, err := strconv.ParseUint(value, 10, 32)
+	if err != nil {
+		return 0, apperror.Validation("invalid cursor")
+	}
+	return uint(id), nil
+}
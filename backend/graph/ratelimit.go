@@ -0,0 +1,272 @@
+package graph
+
+import (
+	"context"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/logging"
+	"elasticsearch-sample/backend/internal/ratelimit"
+)
+
+const rateLimitStateKey contextKey = "rateLimitState"
+
+// 検索エンジンに問い合わせるため、検索用の制限を適用するフィールド
+var rateLimitSearchFields = map[string]bool{
+	"searchArticles": true,
+}
+
+// RateLimitConfig 操作の種類ごとのレート制限
+type RateLimitConfig struct {
+	Search   ratelimit.Limit // 検索フィールドを含むクエリ
+	Mutation ratelimit.Limit
+	Query    ratelimit.Limit // 検索以外のクエリ
+	IP       ratelimit.Limit // 認証前にクライアントIPごとに数えるリクエスト数(IPMiddleware)
+
+	// X-Forwarded-ForからクライアントIPを取得する(リバースプロキシの背後で動かす場合)
+	// 先頭の値はクライアントが自由に設定できるため、信頼するプロキシが追記した末尾から数える
+	TrustForwardedFor bool
+	TrustedProxies    int // X-Forwarded-Forに追記するリバースプロキシの段数
+}
+
+// RateLimiter ユーザー(未ログインの場合はクライアントIP)ごとにGraphQL操作の回数を制限する
+// 認証前のリクエスト数はIPMiddlewareでクライアントIPごとに制限する
+// GraphQLの操作の種類はリクエストを解析するまでわからないため、
+// gqlgenの拡張としてトークンを消費し、HTTPのミドルウェアで429を返す
+type RateLimiter struct {
+	store  ratelimit.Store
+	config RateLimitConfig
+}
+
+// rateLimitState 1リクエスト分の制限状態(ミドルウェアと拡張の間で共有する)
+type rateLimitState struct {
+	clientIP string
+
+	mu         sync.Mutex
+	limited    bool
+	retryAfter time.Duration
+}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.OperationInterceptor
+} = &RateLimiter{}
+
+func NewRateLimiter(store ratelimit.Store, config RateLimitConfig) *RateLimiter {
+	return &RateLimiter{store: store, config: config}
+}
+
+func (l *RateLimiter) ExtensionName() string {
+	return "RateLimiter"
+}
+
+func (l *RateLimiter) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptOperation 操作の種類に応じたバケットからトークンを取得し、足りなければ実行しない
+func (l *RateLimiter) InterceptOperation(ctx context.Context, next graphql.OperationHandler) graphql.ResponseHandler {
+	state, ok := ctx.Value(rateLimitStateKey).(*rateLimitState)
+	if !ok {
+		return next(ctx)
+	}
+
+	opCtx := graphql.GetOperationContext(ctx)
+	if opCtx.Operation == nil {
+		return next(ctx)
+	}
+
+	category, limit := l.limitFor(opCtx.Operation, opCtx.Doc)
+	if limit.Unlimited() {
+		return next(ctx)
+	}
+
+	// ログインユーザーはUID、未ログインはクライアントIPごとに制限
+	key := "ip:" + state.clientIP
+	if userUID, ok := GetUserUIDFromContext(ctx); ok {
+		key = "user:" + userUID
+	}
+
+	result, err := l.store.Take(ctx, category+":"+key, limit, time.Now())
+	if err != nil {
+		// ストアの障害でサービス全体を止めないよう、制限せずに通す
+		logging.FromContext(ctx).WarnContext(ctx, "⚠️ レート制限の確認に失敗しました", "error", err)
+		return next(ctx)
+	}
+	if result.Allowed {
+		return next(ctx)
+	}
+
+	state.mu.Lock()
+	state.limited = true
+	state.retryAfter = result.RetryAfter
+	state.mu.Unlock()
+
+	return graphql.OneShot(&graphql.Response{
+		Errors: gqlerror.List{{
+			Message: "rate limit exceeded",
+			Extensions: map[string]any{
+				"code":       apperror.CodeRateLimited,
+				"retryAfter": retryAfterSeconds(result.RetryAfter),
+			},
+		}},
+	})
+}
+
+// limitFor 操作に適用する制限を返す
+func (l *RateLimiter) limitFor(op *ast.OperationDefinition, doc *ast.QueryDocument) (string, ratelimit.Limit) {
+	switch op.Operation {
+	case ast.Mutation:
+		return "mutation", l.config.Mutation
+	case ast.Query:
+		if selectsAny(op.SelectionSet, doc, rateLimitSearchFields) {
+			return "search", l.config.Search
+		}
+		return "query", l.config.Query
+	default:
+		// Subscriptionは接続時のみのため制限しない
+		return "", ratelimit.Limit{}
+	}
+}
+
+// Middleware リクエストの制限状態をContextに入れ、制限された場合は429とRetry-Afterを返す
+func (l *RateLimiter) Middleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// WebSocketの接続はレスポンスを差し替えられないため対象外
+			if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			state := &rateLimitState{clientIP: l.clientIP(r)}
+			ctx := context.WithValue(r.Context(), rateLimitStateKey, state)
+			next.ServeHTTP(&rateLimitResponseWriter{ResponseWriter: w, state: state}, r.WithContext(ctx))
+		})
+	}
+}
+
+// IPMiddleware 認証の前にクライアントIPごとにリクエスト数を制限し、超えた場合は429とRetry-Afterを返す
+// 不正なトークンやAPIキーのリクエストは認証で拒否され、操作ごとの制限に達しないため、認証情報の総当たりをここで制限する
+func (l *RateLimiter) IPMiddleware() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if l.config.IP.Unlimited() {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := r.Context()
+			result, err := l.store.Take(ctx, "request:ip:"+l.clientIP(r), l.config.IP, time.Now())
+			if err != nil {
+				// ストアの障害でサービス全体を止めないよう、制限せずに通す
+				logging.FromContext(ctx).WarnContext(ctx, "⚠️ レート制限の確認に失敗しました", "error", err)
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !result.Allowed {
+				w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(result.RetryAfter)))
+				writeError(w, http.StatusTooManyRequests, apperror.CodeRateLimited, "rate limit exceeded")
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// clientIP リクエスト元のIPアドレスを返す
+func (l *RateLimiter) clientIP(r *http.Request) string {
+	if l.config.TrustForwardedFor {
+		if ip, ok := forwardedClientIP(r.Header.Values("X-Forwarded-For"), l.config.TrustedProxies); ok {
+			return ip
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// forwardedClientIP 信頼するプロキシの段数をもとにX-Forwarded-ForからクライアントIPを取得する
+// 末尾からproxies番目が最も外側のプロキシが追記したアドレスになる
+// 値が足りない場合はプロキシを経由していないため、X-Forwarded-Forを使わない
+func forwardedClientIP(headers []string, proxies int) (string, bool) {
+	var addrs []string
+	for _, header := range headers {
+		for addr := range strings.SplitSeq(header, ",") {
+			addrs = append(addrs, strings.TrimSpace(addr))
+		}
+	}
+	if proxies < 1 || len(addrs) < proxies {
+		return "", false
+	}
+	ip := addrs[len(addrs)-proxies]
+	if ip == "" {
+		return "", false
+	}
+	return ip, true
+}
+
+// rateLimitResponseWriter 制限された場合にステータスコードを429に差し替える
+type rateLimitResponseWriter struct {
+	http.ResponseWriter
+	state       *rateLimitState
+	wroteHeader bool
+}
+
+func (w *rateLimitResponseWriter) WriteHeader(statusCode int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.state.mu.Lock()
+		if w.state.limited {
+			w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds(w.state.retryAfter)))
+			statusCode = http.StatusTooManyRequests
+		}
+		w.state.mu.Unlock()
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (w *rateLimitResponseWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// selectsAny 選択セット(フラグメントを含む)に指定したフィールドがあるか
+func selectsAny(selections ast.SelectionSet, doc *ast.QueryDocument, fields map[string]bool) bool {
+	for _, selection := range selections {
+		switch s := selection.(type) {
+		case *ast.Field:
+			if fields[s.Name] {
+				return true
+			}
+		case *ast.InlineFragment:
+			if selectsAny(s.SelectionSet, doc, fields) {
+				return true
+			}
+		case *ast.FragmentSpread:
+			if fragment := doc.Fragments.ForName(s.Name); fragment != nil && selectsAny(fragment.SelectionSet, doc, fields) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// retryAfterSeconds Retry-Afterに設定する秒数(切り上げ)
+func retryAfterSeconds(d time.Duration) int {
+	return int(math.Max(1, math.Ceil(d.Seconds())))
+}
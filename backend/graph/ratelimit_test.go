@@ -0,0 +1,122 @@
+package graph
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/ratelimit"
+)
+
+func TestRateLimiterClientIP(t *testing.T) {
+	tests := []struct {
+		name      string
+		config    RateLimitConfig
+		forwarded []string
+		want      string
+	}{
+		{
+			name:      "forwarded for is ignored by default",
+			forwarded: []string{"203.0.113.1"},
+			want:      "192.0.2.10",
+		},
+		{
+			name:      "rightmost entry with one proxy",
+			config:    RateLimitConfig{TrustForwardedFor: true, TrustedProxies: 1},
+			forwarded: []string{"198.51.100.99, 203.0.113.1"},
+			want:      "203.0.113.1",
+		},
+		{
+			name:      "entry added by outermost of two proxies",
+			config:    RateLimitConfig{TrustForwardedFor: true, TrustedProxies: 2},
+			forwarded: []string{"198.51.100.99, 203.0.113.1, 10.0.0.2"},
+			want:      "203.0.113.1",
+		},
+		{
+			name:      "multiple headers are joined",
+			config:    RateLimitConfig{TrustForwardedFor: true, TrustedProxies: 2},
+			forwarded: []string{"198.51.100.99", "203.0.113.1", "10.0.0.2"},
+			want:      "203.0.113.1",
+		},
+		{
+			name:      "fewer entries than proxies",
+			config:    RateLimitConfig{TrustForwardedFor: true, TrustedProxies: 2},
+			forwarded: []string{"203.0.113.1"},
+			want:      "192.0.2.10",
+		},
+		{
+			name:   "no header",
+			config: RateLimitConfig{TrustForwardedFor: true, TrustedProxies: 1},
+			want:   "192.0.2.10",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/query", nil)
+			r.RemoteAddr = "192.0.2.10:54321"
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+
+			limiter := NewRateLimiter(nil, tt.config)
+			if got := limiter.clientIP(r); got != tt.want {
+				t.Errorf("clientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want int
+	}{
+		{0, 1},
+		{100 * time.Millisecond, 1},
+		{time.Second, 1},
+		{1500 * time.Millisecond, 2},
+		{time.Minute, 60},
+	}
+	for _, tt := range tests {
+		if got := retryAfterSeconds(tt.d); got != tt.want {
+			t.Errorf("retryAfterSeconds(%s) = %d, want %d", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestRateLimiterIPMiddleware(t *testing.T) {
+	limiter := NewRateLimiter(ratelimit.NewMemoryStore(), RateLimitConfig{IP: ratelimit.PerMinute(60, 2)})
+	// 認証で拒否される場合も制限の対象になる
+	handler := limiter.IPMiddleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, http.StatusUnauthorized, apperror.CodeUnauthenticated, "invalid token")
+	}))
+
+	request := func(remoteAddr string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("POST", "/query", nil)
+		r.RemoteAddr = remoteAddr
+		r.Header.Set("Authorization", "Bearer guessed")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	for i := range 2 {
+		if w := request("192.0.2.10:1000"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("request %d: status = %d, want %d", i+1, w.Code, http.StatusUnauthorized)
+		}
+	}
+	w := request("192.0.2.10:1001")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("request over burst: status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if w.Header().Get("Retry-After") != "1" {
+		t.Errorf("Retry-After = %q, want 1", w.Header().Get("Retry-After"))
+	}
+
+	// 別のIPアドレスは影響を受けない
+	if w := request("192.0.2.11:1000"); w.Code != http.StatusUnauthorized {
+		t.Errorf("other ip: status = %d, want %d", w.Code, http.StatusUnauthorized)
+	}
+}
@@ -1,10 +1,17 @@
 package graph
 
 import (
+	"elasticsearch-sample/backend/internal/domain/event"
 	"elasticsearch-sample/backend/internal/usecase"
 )
 
 type Resolver struct {
-	ArticleUsecase usecase.ArticleUsecase
-	UserUsecase    usecase.UserUsecase
+	ArticleUsecase    usecase.ArticleUsecase
+	UserUsecase       usecase.UserUsecase
+	APIKeyUsecase     usecase.APIKeyUsecase
+	CommentUsecase    usecase.CommentUsecase
+	AttachmentUsecase usecase.AttachmentUsecase
+	LikeUsecase       usecase.LikeUsecase
+	BookmarkUsecase   usecase.BookmarkUsecase
+	ArticleEvents     event.ArticleEventSubscriber
 }
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"context"
+
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	entity "elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/usecase"
+)
+
+// fakeUserUsecase テスト用のユーザー一覧 (使わないメソッドは埋め込んだnilのインターフェースに任せる)
+type fakeUserUsecase struct {
+	usecase.UserUsecase
+	users []*entity.User
+}
+
+func (f *fakeUserUsecase) GetUserByUID(ctx context.Context, userUID string) (*entity.User, error) {
+	for _, user := range f.users {
+		if user.UID == userUID {
+			return user, nil
+		}
+	}
+	return nil, apperror.NotFound("user not found")
+}
+
+// fakeArticleUsecase テスト用の記事一覧
+type fakeArticleUsecase struct {
+	usecase.ArticleUsecase
+	articles []*entity.Article
+	updated  []usecase.UpdateArticleInput
+	listed   []int // ListArticlesに渡されたpageSize
+}
+
+func (f *fakeArticleUsecase) GetArticleByID(ctx context.Context, articleID uint) (*entity.Article, error) {
+	for _, article := range f.articles {
+		if article.ID == articleID {
+			return article, nil
+		}
+	}
+	return nil, apperror.NotFound("article not found")
+}
+
+func (f *fakeArticleUsecase) ListArticles(ctx context.Context, viewerID uint, page int, pageSize int) ([]*entity.Article, error) {
+	f.listed = append(f.listed, pageSize)
+	start := min((page-1)*pageSize, len(f.articles))
+	end := min(start+pageSize, len(f.articles))
+	return f.articles[start:end], nil
+}
+
+func (f *fakeArticleUsecase) UpdateArticle(ctx context.Context, input usecase.UpdateArticleInput) (*entity.Article, error) {
+	f.updated = append(f.updated, input)
+	article, err := f.GetArticleByID(ctx, input.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	if input.Status != nil {
+		article.Status = *input.Status
+	}
+	return article, nil
+}
+
+// newTestResolver 作成者(author)と他のユーザー(other)、authorの下書き記事(ID: 1)を持つResolver
+func newTestResolver() (*Resolver, *fakeArticleUsecase) {
+	author := &entity.User{UID: "author"}
+	author.ID = 1
+	other := &entity.User{UID: "other"}
+	other.ID = 2
+	draft := &entity.Article{UserID: author.ID, Title: "draft", Status: "draft"}
+	draft.ID = 1
+
+	articles := &fakeArticleUsecase{articles: []*entity.Article{draft}}
+	return &Resolver{
+		UserUsecase:    &fakeUserUsecase{users: []*entity.User{author, other}},
+		ArticleUsecase: articles,
+	}, articles
+}
+
+// withUser ログインユーザーを設定したContext
+func withUser(uid string) context.Context {
+	return context.WithValue(context.Background(), userIDKey, uid)
+}
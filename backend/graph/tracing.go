@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("elasticsearch-sample/backend/graph")
+
+// Tracing GraphQL操作とResolverごとにスパンを作成するgqlgenの拡張
+// 構造体のフィールドを返すだけのフィールドはスパンを作成しない
+type Tracing struct{}
+
+var _ interface {
+	graphql.HandlerExtension
+	graphql.ResponseInterceptor
+	graphql.FieldInterceptor
+} = Tracing{}
+
+func (Tracing) ExtensionName() string {
+	return "Tracing"
+}
+
+func (Tracing) Validate(schema graphql.ExecutableSchema) error {
+	return nil
+}
+
+// InterceptResponse 操作全体のスパンを作成する (Subscriptionはイベントごとに作成される)
+func (Tracing) InterceptResponse(ctx context.Context, next graphql.ResponseHandler) *graphql.Response {
+	if !graphql.HasOperationContext(ctx) {
+		return next(ctx)
+	}
+
+	opCtx := graphql.GetOperationContext(ctx)
+	operationType := "unknown"
+	if opCtx.Operation != nil {
+		operationType = string(opCtx.Operation.Operation)
+	}
+	spanName := "graphql." + operationType
+	if opCtx.OperationName != "" {
+		spanName += " " + opCtx.OperationName
+	}
+
+	options := []trace.SpanStartOption{
+		trace.WithAttributes(
+			attribute.String("graphql.operation.type", operationType),
+			attribute.String("graphql.operation.name", opCtx.OperationName),
+		),
+	}
+	// 解析・検証の時間も含めるため、操作の開始時刻から計測する
+	if operationType != string(ast.Subscription) {
+		options = append(options, trace.WithTimestamp(opCtx.Stats.OperationStart))
+	}
+
+	ctx, span := tracer.Start(ctx, spanName, options...)
+	defer span.End()
+
+	resp := next(ctx)
+	if resp != nil && len(resp.Errors) > 0 {
+		span.SetStatus(codes.Error, resp.Errors.Error())
+	}
+	return resp
+}
+
+// InterceptField Resolverを呼び出すフィールドのスパンを作成する
+func (Tracing) InterceptField(ctx context.Context, next graphql.Resolver) (any, error) {
+	fc := graphql.GetFieldContext(ctx)
+	if fc == nil || !fc.IsResolver {
+		return next(ctx)
+	}
+
+	ctx, span := tracer.Start(ctx, fmt.Sprintf("%s.%s", fc.Object, fc.Field.Name),
+		trace.WithAttributes(
+			attribute.String("graphql.field.path", fc.Path().String()),
+		),
+	)
+	defer span.End()
+
+	res, err := next(ctx)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	return res, err
+}
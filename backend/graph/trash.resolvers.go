@@ -0,0 +1,156 @@
+package graph
+
+import (
+	"context"
+
+	model "elasticsearch-sample/backend/graph/model"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	entity "elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/usecase"
+)
+
+// ========================
+// 汎用関数
+// ========================
+// requireDeletedArticleAuthor ログインユーザーがゴミ箱の記事の作成者か確認する
+func (r *Resolver) requireDeletedArticleAuthor(ctx context.Context, articleID uint) (*entity.Article, error) {
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	article, err := r.ArticleUsecase.GetDeletedArticleByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+	if article.UserID != user.ID {
+		// 他のユーザーのゴミ箱の中身は存在しないものとして扱う
+		return nil, apperror.NotFound("deleted article not found")
+	}
+	return article, nil
+}
+
+// ========================
+// Mutation
+// ========================
+
+func (r *mutationResolver) DeleteArticle(ctx context.Context, input model.DeleteArticleInput) (*model.Article, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+	articleID, err := parseID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.requireArticleAuthor(ctx, articleID); err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し(ゴミ箱に移動し、検索対象から外す)
+	if err := r.ArticleUsecase.DeleteArticle(ctx, articleID); err != nil {
+		return nil, err
+	}
+	deletedArticle, err := r.ArticleUsecase.GetDeletedArticleByID(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelArticle(*deletedArticle), nil
+}
+
+func (r *mutationResolver) RestoreArticle(ctx context.Context, input model.RestoreArticleInput) (*model.Article, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return nil, err
+	}
+	articleID, err := parseID(input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.requireDeletedArticleAuthor(ctx, articleID); err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し(検索エンジンにも再登録される)
+	restoredArticle, err := r.ArticleUsecase.RestoreArticle(ctx, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelArticle(*restoredArticle), nil
+}
+
+func (r *mutationResolver) PurgeArticle(ctx context.Context, input model.PurgeArticleInput) (bool, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesWrite); err != nil {
+		return false, err
+	}
+	articleID, err := parseID(input.ID)
+	if err != nil {
+		return false, err
+	}
+	if _, err := r.requireDeletedArticleAuthor(ctx, articleID); err != nil {
+		return false, err
+	}
+
+	// Usecaseの呼び出し(ゴミ箱にある記事のみ削除できる)
+	if err := r.ArticleUsecase.PurgeArticle(ctx, articleID); err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// ========================
+// Query
+// ========================
+
+func (r *queryResolver) DeletedArticles(ctx context.Context, first *int32, after *string) (*model.ArticleConnection, error) {
+	// ログインユーザーの取得
+	user, err := r.currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
+	limit, err := pageSize(first)
+	if err != nil {
+		return nil, err
+	}
+	afterID, err := DecodeCursor("article", after)
+	if err != nil {
+		return nil, err
+	}
+
+	// Usecaseの呼び出し(次ページの有無を判定するため1件多く取得)
+	articles, totalCount, err := r.ArticleUsecase.ListDeletedArticles(ctx, usecase.ListDeletedArticlesInput{
+		UserID:  user.ID,
+		AfterID: afterID,
+		Limit:   limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// モデル変換
+	hasNextPage := len(articles) > limit
+	if hasNextPage {
+		articles = articles[:limit]
+	}
+	edges := make([]*model.ArticleEdge, 0, len(articles))
+	for _, article := range articles {
+		edges = append(edges, &model.ArticleEdge{
+			Cursor: EncodeCursor("article", article.ID),
+			Node:   ToModelArticle(*article),
+		})
+	}
+	pageInfo := &model.PageInfo{HasNextPage: hasNextPage}
+	if len(edges) > 0 {
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &model.ArticleConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: int32(totalCount),
+	}, nil
+}
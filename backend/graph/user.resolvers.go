@@ -3,12 +3,16 @@ package graph
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"strings"
 
 	model "elasticsearch-sample/backend/graph/model"
+	"elasticsearch-sample/backend/internal/domain/apperror"
 	entity "elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/usecase"
 )
 
-func (r *Resolver) User() ArticleResolver { return &articleResolver{r} }
+func (r *Resolver) User() UserResolver { return &userResolver{r} }
 
 type userResolver struct{ *Resolver }
 
@@ -17,8 +21,9 @@ type userResolver struct{ *Resolver }
 // ========================
 func ToModelUser(user entity.User) *model.User {
 	return &model.User{
-		ID:  fmt.Sprintf("%d", user.ID),
-		UID: user.UID,
+		ID:          fmt.Sprintf("%d", user.ID),
+		UID:         user.UID,
+		DisplayName: user.DisplayName,
 	}
 }
 
@@ -26,6 +31,72 @@ func ToModelUser(user entity.User) *model.User {
 // Resolver
 // ========================
 
+func (r *userResolver) Articles(ctx context.Context, obj *model.User, first *int32, after *string, status *model.ArticleStatus) (*model.ArticleConnection, error) {
+	if err := requireScope(ctx, entity.ScopeArticlesRead); err != nil {
+		return nil, err
+	}
+
+	userID, err := strconv.ParseUint(obj.ID, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	limit, err := pageSize(first)
+	if err != nil {
+		return nil, err
+	}
+	afterID, err := DecodeCursor("article", after)
+	if err != nil {
+		return nil, err
+	}
+
+	// 下書き・アーカイブは本人のみ閲覧可能
+	userUID, _ := GetUserUIDFromContext(ctx)
+	isOwner := userUID != "" && userUID == obj.UID
+	var statuses []string
+	switch {
+	case status != nil && (isOwner || *status == model.ArticleStatusPublished):
+		statuses = []string{strings.ToLower(string(*status))}
+	case status != nil:
+		return &model.ArticleConnection{Edges: []*model.ArticleEdge{}, PageInfo: &model.PageInfo{}}, nil
+	case !isOwner:
+		statuses = []string{"published"}
+	}
+
+	// Usecaseの呼び出し(次ページの有無を判定するため1件多く取得)
+	articles, totalCount, err := r.ArticleUsecase.ListUserArticles(ctx, usecase.ListUserArticlesInput{
+		UserID:   uint(userID),
+		Statuses: statuses,
+		AfterID:  afterID,
+		Limit:    limit + 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// モデル変換
+	hasNextPage := len(articles) > limit
+	if hasNextPage {
+		articles = articles[:limit]
+	}
+	edges := make([]*model.ArticleEdge, 0, len(articles))
+	for _, article := range articles {
+		edges = append(edges, &model.ArticleEdge{
+			Cursor: EncodeCursor("article", article.ID),
+			Node:   ToModelArticle(*article),
+		})
+	}
+	pageInfo := &model.PageInfo{HasNextPage: hasNextPage}
+	if len(edges) > 0 {
+		pageInfo.EndCursor = &edges[len(edges)-1].Cursor
+	}
+
+	return &model.ArticleConnection{
+		Edges:      edges,
+		PageInfo:   pageInfo,
+		TotalCount: int32(totalCount),
+	}, nil
+}
+
 // ========================
 // Mutation
 // ========================
@@ -38,7 +109,7 @@ func (r *queryResolver) CurrentUser(ctx context.Context) (*model.User, error) {
 	// ログインユーザーIDの取得
 	userUID, ok := GetUserUIDFromContext(ctx)
 	if !ok {
-		return nil, fmt.Errorf("authentication required")
+		return nil, apperror.Unauthenticated("authentication required")
 	}
 
 	// Usecaseの呼び出し
@@ -49,3 +120,27 @@ func (r *queryResolver) CurrentUser(ctx context.Context) (*model.User, error) {
 
 	return ToModelUser(*user), nil
 }
+
+func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
+	// Usecaseの呼び出し
+	userID, err := parseID(id)
+	if err != nil {
+		return nil, err
+	}
+	user, err := r.UserUsecase.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelUser(*user), nil
+}
+
+func (r *queryResolver) UserByUID(ctx context.Context, uid string) (*model.User, error) {
+	// Usecaseの呼び出し
+	user, err := r.UserUsecase.GetUserByUID(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToModelUser(*user), nil
+}
@@ -0,0 +1,177 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+
+	"elasticsearch-sample/backend/internal/config"
+	"elasticsearch-sample/backend/internal/domain/event"
+	"elasticsearch-sample/backend/internal/domain/repository"
+	"elasticsearch-sample/backend/internal/health"
+	"elasticsearch-sample/backend/internal/infrastructure/db"
+	"elasticsearch-sample/backend/internal/infrastructure/es"
+	"elasticsearch-sample/backend/internal/infrastructure/pubsub"
+	"elasticsearch-sample/backend/internal/infrastructure/storage"
+	"elasticsearch-sample/backend/internal/infrastructure/tracing"
+	"elasticsearch-sample/backend/internal/usecase"
+	"elasticsearch-sample/backend/internal/worker"
+)
+
+// App 設定から組み立てたアプリケーションの依存関係
+// cmd配下の各コマンドはAppから必要なUsecaseを取り出して使う
+type App struct {
+	Lifecycle
+
+	Config *config.Config
+	DB     *gorm.DB
+	ES     *es.Client
+	Blobs  repository.BlobStore // 添付ファイルの保存先
+	Health *health.Checker
+
+	// 記事イベントのPub/Sub
+	ArticleEvents *pubsub.Broker[event.ArticleEvent]
+
+	ArticleUsecase    usecase.ArticleUsecase
+	UserUsecase       usecase.UserUsecase
+	APIKeyUsecase     usecase.APIKeyUsecase
+	CommentUsecase    usecase.CommentUsecase
+	AttachmentUsecase usecase.AttachmentUsecase
+	LikeUsecase       usecase.LikeUsecase
+	BookmarkUsecase   usecase.BookmarkUsecase
+}
+
+// Option Newで初期化する内容を変更する
+type Option func(*options)
+
+type options struct {
+	withoutStorage bool
+}
+
+// WithoutStorage 添付ファイルの保存先を初期化しない (S3のバケットなどを作成しない)
+// シードや再インデックスなど、添付ファイルを扱わないコマンドで使う
+func WithoutStorage() Option {
+	return func(o *options) { o.withoutStorage = true }
+}
+
+// New データベースとElasticsearchに接続し、RepositoryとUsecaseを初期化する
+// 接続はStopで閉じられる (初期化に失敗した場合は、それまでに開いた接続を閉じてからエラーを返す)
+func New(cfg *config.Config, opts ...Option) (*App, error) {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	a := &App{Config: cfg}
+	if err := a.init(cfg, o); err != nil {
+		return nil, errors.Join(err, a.Stop(context.Background()))
+	}
+	return a, nil
+}
+
+// init リソースを初期化する (リソースを確保したらすぐに解放処理を登録する)
+func (a *App) init(cfg *config.Config, o options) error {
+	// トレースの初期化 (DBやESの計装より先に行う)
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.Tracing)
+	if err != nil {
+		return err
+	}
+	a.Append(Hook{
+		Name:   "tracing",
+		OnStop: shutdownTracing,
+	})
+
+	// Infrastructure初期化
+	database, err := db.Connect(cfg.Database)
+	if err != nil {
+		return err
+	}
+	a.DB = database
+	a.Append(Hook{
+		Name:   "database",
+		OnStop: func(ctx context.Context) error { return db.Close(database) },
+	})
+
+	a.ES, err = es.NewClient(cfg.Elasticsearch)
+	if err != nil {
+		return err
+	}
+	a.Append(Hook{
+		Name:   "elasticsearch",
+		OnStop: a.ES.Close,
+	})
+
+	// 依存先の疎通確認
+	checks := []health.Check{
+		{Name: "postgres", Check: func(ctx context.Context) error { return db.Ping(ctx, a.DB) }},
+		{Name: "elasticsearch", Check: func(ctx context.Context) error { return a.ES.Ping(ctx, es.ArticleIndexName) }},
+	}
+
+	if o.withoutStorage {
+		a.Blobs = storage.Disabled()
+	} else {
+		a.Blobs, err = storage.New(cfg.Storage)
+		if err != nil {
+			return err
+		}
+		checks = append(checks, health.Check{Name: "storage", Check: a.Blobs.Ping})
+	}
+	a.Health = health.NewChecker(checks...)
+
+	// Repository初期化
+	articleDBRepo := repository.NewArticleRepository(a.DB)
+	articleRevisionRepo := repository.NewArticleRevisionRepository(a.DB)
+	articleSearchRepo := es.NewArticleSearchRepository(a.ES)
+	userDBRepo := repository.NewUserRepository(a.DB)
+	apiKeyDBRepo := repository.NewAPIKeyRepository(a.DB)
+	commentDBRepo := repository.NewCommentRepository(a.DB)
+	attachmentDBRepo := repository.NewAttachmentRepository(a.DB)
+	likeDBRepo := repository.NewLikeRepository(a.DB)
+	bookmarkDBRepo := repository.NewBookmarkRepository(a.DB)
+
+	// 記事イベントのPub/Sub初期化 (購読者がいない場合、イベントは破棄される)
+	a.ArticleEvents = pubsub.NewBroker[event.ArticleEvent]()
+
+	// Usecase初期化
+	a.AttachmentUsecase = usecase.NewAttachmentUsecase(attachmentDBRepo, a.Blobs, int64(cfg.Storage.MaxUploadBytes))
+	a.ArticleUsecase = usecase.NewArticleUsecase(repository.NewTransactor(a.DB), articleDBRepo, articleRevisionRepo, articleSearchRepo, a.AttachmentUsecase, a.ArticleEvents)
+	a.UserUsecase = usecase.NewUserUsecase(userDBRepo)
+	a.APIKeyUsecase = usecase.NewAPIKeyUsecase(apiKeyDBRepo)
+	a.CommentUsecase = usecase.NewCommentUsecase(commentDBRepo, articleDBRepo)
+	a.LikeUsecase = usecase.NewLikeUsecase(likeDBRepo, articleDBRepo, articleSearchRepo)
+	a.BookmarkUsecase = usecase.NewBookmarkUsecase(bookmarkDBRepo, articleDBRepo)
+
+	return nil
+}
+
+// AppendWorker ワーカーをStartで起動し、Stopで終了を待つHookを登録する
+func (a *App) AppendWorker(w *worker.PeriodicWorker) {
+	var (
+		cancel context.CancelFunc
+		done   chan struct{}
+	)
+	a.Append(Hook{
+		Name: w.Name(),
+		OnStart: func(context.Context) error {
+			// ワーカーはStartのctxではなく、Stopが呼ばれるまで動き続ける
+			var ctx context.Context
+			ctx, cancel = context.WithCancel(context.Background())
+			done = make(chan struct{})
+			go func() {
+				defer close(done)
+				w.Run(ctx)
+			}()
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		},
+	})
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"elasticsearch-sample/backend/internal/logging"
+)
+
+var logger = logging.Component("app")
+
+// Hook コンポーネントの開始・停止処理 (不要な方はnilでよい)
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+// Lifecycle 登録されたHookを登録順に開始し、逆順に停止する
+type Lifecycle struct {
+	hooks   []Hook
+	started int // 開始済みのHookの数
+}
+
+// Append Hookを登録する
+func (l *Lifecycle) Append(hook Hook) {
+	l.hooks = append(l.hooks, hook)
+}
+
+// Start 未開始のHookを登録順に開始する
+// 失敗した場合は開始済みのHookを停止してエラーを返す
+func (l *Lifecycle) Start(ctx context.Context) error {
+	for l.started < len(l.hooks) {
+		hook := l.hooks[l.started]
+		if hook.OnStart != nil {
+			if err := hook.OnStart(ctx); err != nil {
+				startErr := fmt.Errorf("❌ '%s' の開始に失敗しました: %w", hook.Name, err)
+				return errors.Join(startErr, l.Stop(ctx))
+			}
+		}
+		l.started++
+	}
+	return nil
+}
+
+// Stop 登録されたHookを逆順に停止し、登録を解除する (途中で失敗しても残りのHookは停止する)
+// OnStartのないHookは登録時点でリソースを確保しているため、Startの前後にかかわらず停止する
+// OnStartのあるHookは、開始済みの場合のみ停止する
+func (l *Lifecycle) Stop(ctx context.Context) error {
+	var errs []error
+	for i := len(l.hooks) - 1; i >= 0; i-- {
+		hook := l.hooks[i]
+		if hook.OnStop == nil || (hook.OnStart != nil && i >= l.started) {
+			continue
+		}
+		if err := hook.OnStop(ctx); err != nil {
+			logger.ErrorContext(ctx, "❌ 停止に失敗しました", "hook", hook.Name, "error", err)
+			errs = append(errs, fmt.Errorf("'%s' の停止に失敗しました: %w", hook.Name, err))
+		}
+	}
+	l.hooks, l.started = nil, 0
+	return errors.Join(errs...)
+}
@@ -0,0 +1,67 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+// recordHook 開始・停止した順序を記録するHookを返す
+func recordHook(name string, withStart bool, log *[]string, startErr error) Hook {
+	hook := Hook{
+		Name: name,
+		OnStop: func(context.Context) error {
+			*log = append(*log, "stop "+name)
+			return nil
+		},
+	}
+	if withStart {
+		hook.OnStart = func(context.Context) error {
+			*log = append(*log, "start "+name)
+			return startErr
+		}
+	}
+	return hook
+}
+
+func TestLifecycleStopWithoutStart(t *testing.T) {
+	var log []string
+	var l Lifecycle
+	l.Append(recordHook("db", false, &log, nil))
+	l.Append(recordHook("worker", true, &log, nil))
+	l.Append(recordHook("es", false, &log, nil))
+
+	if err := l.Stop(context.Background()); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	// OnStartのないHookは開始前でも停止し、開始していないワーカーは停止しない
+	if want := []string{"stop es", "stop db"}; !slices.Equal(log, want) {
+		t.Errorf("log = %v, want %v", log, want)
+	}
+
+	// 停止済みのHookは再び停止しない
+	log = nil
+	if err := l.Stop(context.Background()); err != nil || len(log) != 0 {
+		t.Errorf("second Stop() = %v, log = %v, want no hooks stopped", err, log)
+	}
+}
+
+func TestLifecycleStartFailure(t *testing.T) {
+	errStart := errors.New("start failed")
+	var log []string
+	var l Lifecycle
+	l.Append(recordHook("db", false, &log, nil))
+	l.Append(recordHook("worker", true, &log, nil))
+	l.Append(recordHook("broken", true, &log, errStart))
+	l.Append(recordHook("es", false, &log, nil))
+
+	if err := l.Start(context.Background()); !errors.Is(err, errStart) {
+		t.Fatalf("Start() error = %v, want %v", err, errStart)
+	}
+	// 失敗したHookより後に登録されたリソースも解放する
+	want := []string{"start worker", "start broken", "stop es", "stop worker", "stop db"}
+	if !slices.Equal(log, want) {
+		t.Errorf("log = %v, want %v", log, want)
+	}
+}
@@ -0,0 +1,415 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ログレベルとして指定できる値
+var logLevels = []string{"debug", "info", "warn", "error"}
+
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
+)
+
+// Config アプリケーション全体の設定
+// デフォルト値 → 設定ファイル(CONFIG_FILE) → 環境変数 の順に上書きして読み込む
+type Config struct {
+	Env           string              `yaml:"env"` // development / production
+	Server        ServerConfig        `yaml:"server"`
+	Database      DatabaseConfig      `yaml:"database"`
+	Elasticsearch ElasticsearchConfig `yaml:"elasticsearch"`
+	Auth          AuthConfig          `yaml:"auth"`
+	GraphQL       GraphQLConfig       `yaml:"graphql"`
+	RateLimit     RateLimitConfig     `yaml:"rate_limit"`
+	Scheduler     SchedulerConfig     `yaml:"scheduler"`
+	Trash         TrashConfig         `yaml:"trash"`
+	Tracing       TracingConfig       `yaml:"tracing"`
+	Log           LogConfig           `yaml:"log"`
+	Storage       StorageConfig       `yaml:"storage"`
+}
+
+// ServerConfig HTTPサーバーの設定
+type ServerConfig struct {
+	Port               int      `yaml:"port"`
+	CORSAllowedOrigins []string `yaml:"cors_allowed_origins"`
+	Playground         bool     `yaml:"playground"` // GraphQL Playgroundを公開するか
+
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"` // 停止時に処理中のリクエストの完了を待つ時間
+	MaxBodyBytes      int           `yaml:"max_body_bytes"`   // リクエストボディの上限サイズ
+}
+
+// DatabaseConfig PostgreSQLの接続設定 (URLを指定した場合は個別の設定より優先)
+type DatabaseConfig struct {
+	URL      Secret `yaml:"url"`
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password Secret `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslmode"`
+
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"` // これより時間のかかったSQLを警告する (0の場合は警告しない)
+}
+
+// ElasticsearchConfig Elasticsearchの接続設定
+type ElasticsearchConfig struct {
+	Addresses []string `yaml:"addresses"`
+	CloudID   string   `yaml:"cloud_id"`
+	APIKey    Secret   `yaml:"api_key"`
+
+	SlowRequestThreshold  time.Duration `yaml:"slow_request_threshold"`   // これより時間のかかったリクエストを警告する (0の場合は警告しない)
+	LikeCountSyncInterval time.Duration `yaml:"like_count_sync_interval"` // いいね数を検索エンジンに反映する間隔
+}
+
+// AuthConfig トークン検証の設定
+type AuthConfig struct {
+	HS256Secret Secret `yaml:"hs256_secret"` // ローカル開発用
+	JWKSURL     string `yaml:"jwks_url"`
+	JWKSFile    string `yaml:"jwks_file"`
+	Issuer      string `yaml:"issuer"`
+	Audience    string `yaml:"audience"`
+}
+
+// GraphQLConfig 1回の操作で許可するコストとネストの深さ (0の場合は制限なし)
+type GraphQLConfig struct {
+	MaxComplexity int `yaml:"max_complexity"`
+	MaxDepth      int `yaml:"max_depth"`
+
+	// メトリクスに操作名をそのまま記録する操作 (それ以外の操作は other として集計する)
+	MetricOperations []string `yaml:"metric_operations"`
+}
+
+// RateLimitConfig 操作の種類ごとのレート制限
+type RateLimitConfig struct {
+	Search            RateLimitRule `yaml:"search"`
+	Mutation          RateLimitRule `yaml:"mutation"`
+	Query             RateLimitRule `yaml:"query"`
+	IP                RateLimitRule `yaml:"ip"`                  // 認証前にクライアントIPごとに適用するリクエスト数の制限(トークンやAPIキーの総当たり対策)
+	TrustForwardedFor bool          `yaml:"trust_forwarded_for"` // X-Forwarded-Forからクライアントのアドレスを取得する(リバースプロキシの背後で動かす場合のみ)
+	TrustedProxies    int           `yaml:"trusted_proxies"`     // X-Forwarded-Forに追記するリバースプロキシの段数
+}
+
+// RateLimitRule 1分あたりの回数とバースト (PerMinuteが0の場合は制限なし)
+type RateLimitRule struct {
+	PerMinute int `yaml:"per_minute"`
+	Burst     int `yaml:"burst"`
+}
+
+// SchedulerConfig 予約公開ワーカーの設定
+type SchedulerConfig struct {
+	Interval time.Duration `yaml:"interval"`
+}
+
+// TrashConfig ゴミ箱の設定
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention"`      // 削除した記事を完全に削除するまでの期間
+	PurgeInterval time.Duration `yaml:"purge_interval"` // 保持期間を過ぎた記事を削除するワーカーの実行間隔
+}
+
+// TracingConfig 分散トレーシングの設定
+type TracingConfig struct {
+	Exporter     string  `yaml:"exporter"`      // none / stdout / otlp
+	OTLPEndpoint string  `yaml:"otlp_endpoint"` // 例: otel-collector:4318 (未指定の場合はOTEL_EXPORTER_OTLP_ENDPOINTなどの標準の環境変数に従う)
+	OTLPInsecure bool    `yaml:"otlp_insecure"` // TLSを使わずに送信する
+	ServiceName  string  `yaml:"service_name"`
+	SampleRatio  float64 `yaml:"sample_ratio"` // 0〜1 (呼び出し元のトレースがある場合はその判定に従う)
+}
+
+// LogConfig ログ出力の設定
+type LogConfig struct {
+	Level      string            `yaml:"level"`      // debug / info / warn / error
+	Format     string            `yaml:"format"`     // json / text
+	Components map[string]string `yaml:"components"` // コンポーネント(db, es, http など)ごとのレベル
+}
+
+// StorageConfig 添付ファイルの保存先の設定
+type StorageConfig struct {
+	Backend        string             `yaml:"backend"`          // local (開発環境専用) / s3
+	MaxUploadBytes int                `yaml:"max_upload_bytes"` // 1ファイルあたりの上限サイズ
+	Local          LocalStorageConfig `yaml:"local"`
+	S3             S3StorageConfig    `yaml:"s3"`
+}
+
+// LocalStorageConfig サーバーのファイルシステムに保存する場合の設定
+type LocalStorageConfig struct {
+	Dir     string `yaml:"dir"`
+	BaseURL string `yaml:"base_url"` // ファイルを配信するURL (パスの部分でサーバーがファイルを配信する)
+}
+
+// S3StorageConfig S3互換のオブジェクトストレージ(MinIOなど)に保存する場合の設定
+type S3StorageConfig struct {
+	Endpoint        string        `yaml:"endpoint"` // 例: s3.amazonaws.com, minio:9000
+	Region          string        `yaml:"region"`
+	Bucket          string        `yaml:"bucket"`
+	AccessKeyID     string        `yaml:"access_key_id"`
+	SecretAccessKey Secret        `yaml:"secret_access_key"`
+	UseSSL          bool          `yaml:"use_ssl"`
+	BaseURL         string        `yaml:"base_url"`         // 公開バケットやCDNのURL (未指定の場合は署名付きURLを返す)
+	PresignedExpiry time.Duration `yaml:"presigned_expiry"` // 署名付きURLの有効期間
+}
+
+// Default 設定ファイルや環境変数で上書きされなかった場合の値
+func Default() *Config {
+	return &Config{
+		Env: EnvDevelopment,
+		Server: ServerConfig{
+			Port:               8080,
+			CORSAllowedOrigins: []string{"*"},
+			Playground:         true,
+			ReadHeaderTimeout:  5 * time.Second,
+			ReadTimeout:        15 * time.Second,
+			WriteTimeout:       30 * time.Second,
+			IdleTimeout:        2 * time.Minute,
+			ShutdownTimeout:    30 * time.Second,
+			MaxBodyBytes:       1 << 20, // 1MiB
+		},
+		Database: DatabaseConfig{
+			Port:               5432,
+			SSLMode:            "disable",
+			SlowQueryThreshold: 200 * time.Millisecond,
+		},
+		Elasticsearch: ElasticsearchConfig{
+			SlowRequestThreshold:  time.Second,
+			LikeCountSyncInterval: time.Minute,
+		},
+		GraphQL: GraphQLConfig{
+			MaxComplexity: 1000,
+			MaxDepth:      10,
+		},
+		RateLimit: RateLimitConfig{
+			Search:   RateLimitRule{PerMinute: 60, Burst: 20},
+			Mutation: RateLimitRule{PerMinute: 30, Burst: 10},
+			Query:    RateLimitRule{PerMinute: 300, Burst: 100},
+			IP:       RateLimitRule{PerMinute: 600, Burst: 200},
+
+			TrustedProxies: 1,
+		},
+		Scheduler: SchedulerConfig{
+			Interval: time.Minute,
+		},
+		Trash: TrashConfig{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "elasticsearch-sample-backend",
+			SampleRatio: 1,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Storage: StorageConfig{
+			Backend:        "local",
+			MaxUploadBytes: 10 << 20, // 10MiB
+			Local: LocalStorageConfig{
+				Dir:     "uploads",
+				BaseURL: "/files",
+			},
+			S3: S3StorageConfig{
+				UseSSL:          true,
+				PresignedExpiry: time.Hour,
+			},
+		},
+	}
+}
+
+// Load 設定を読み込んで検証する
+func Load() (*Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile YAMLの設定ファイルで上書きする (記載のない項目は現在の値のまま)
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("設定ファイルの読み込みに失敗しました: %w", err)
+	}
+	if err := yaml.UnmarshalWithOptions(data, c, yaml.Strict()); err != nil {
+		return fmt.Errorf("設定ファイル '%s' の形式が不正です: %w", path, err)
+	}
+	return nil
+}
+
+// Validate 設定値の組み合わせや範囲を検証する
+func (c *Config) Validate() error {
+	var errs []error
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		errs = append(errs, fmt.Errorf("env は %s または %s を指定してください: %q", EnvDevelopment, EnvProduction, c.Env))
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port が範囲外です: %d", c.Server.Port))
+	}
+	if c.Server.ReadHeaderTimeout < 0 || c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 {
+		errs = append(errs, errors.New("server のタイムアウトは0以上を指定してください"))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, fmt.Errorf("server.shutdown_timeout は正の値を指定してください: %s", c.Server.ShutdownTimeout))
+	}
+	if c.Server.MaxBodyBytes <= 0 {
+		errs = append(errs, fmt.Errorf("server.max_body_bytes は正の値を指定してください: %d", c.Server.MaxBodyBytes))
+	}
+
+	if c.Database.URL == "" {
+		if c.Database.Port < 1 || c.Database.Port > 65535 {
+			errs = append(errs, fmt.Errorf("database.port が範囲外です: %d", c.Database.Port))
+		}
+		if !slices.Contains([]string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}, c.Database.SSLMode) {
+			errs = append(errs, fmt.Errorf("database.sslmode が不正です: %q", c.Database.SSLMode))
+		}
+	}
+	if c.Database.SlowQueryThreshold < 0 || c.Elasticsearch.SlowRequestThreshold < 0 {
+		errs = append(errs, errors.New("database.slow_query_threshold / elasticsearch.slow_request_threshold は0以上を指定してください"))
+	}
+	if c.Elasticsearch.LikeCountSyncInterval <= 0 {
+		errs = append(errs, fmt.Errorf("elasticsearch.like_count_sync_interval は正の値を指定してください: %s", c.Elasticsearch.LikeCountSyncInterval))
+	}
+
+	if c.GraphQL.MaxComplexity < 0 || c.GraphQL.MaxDepth < 0 {
+		errs = append(errs, errors.New("graphql.max_complexity / graphql.max_depth は0以上を指定してください"))
+	}
+
+	for _, rule := range []struct {
+		name string
+		RateLimitRule
+	}{
+		{"search", c.RateLimit.Search},
+		{"mutation", c.RateLimit.Mutation},
+		{"query", c.RateLimit.Query},
+		{"ip", c.RateLimit.IP},
+	} {
+		if rule.PerMinute < 0 || rule.Burst < 0 {
+			errs = append(errs, fmt.Errorf("rate_limit.%s は0以上を指定してください", rule.name))
+		}
+		// バーストが0だと制限なしとして扱われるため、制限する場合は1以上を必須にする
+		if rule.PerMinute > 0 && rule.Burst < 1 {
+			errs = append(errs, fmt.Errorf("rate_limit.%s.burst は1以上を指定してください (制限しない場合は per_minute を0にしてください)", rule.name))
+		}
+	}
+	if c.RateLimit.TrustForwardedFor && c.RateLimit.TrustedProxies < 1 {
+		errs = append(errs, fmt.Errorf("rate_limit.trusted_proxies は1以上を指定してください: %d", c.RateLimit.TrustedProxies))
+	}
+
+	if c.Scheduler.Interval <= 0 {
+		errs = append(errs, fmt.Errorf("scheduler.interval は正の値を指定してください: %s", c.Scheduler.Interval))
+	}
+	if c.Trash.Retention <= 0 {
+		errs = append(errs, fmt.Errorf("trash.retention は正の値を指定してください: %s", c.Trash.Retention))
+	}
+	if c.Trash.PurgeInterval <= 0 {
+		errs = append(errs, fmt.Errorf("trash.purge_interval は正の値を指定してください: %s", c.Trash.PurgeInterval))
+	}
+
+	if !slices.Contains([]string{"none", "stdout", "otlp"}, c.Tracing.Exporter) {
+		errs = append(errs, fmt.Errorf("tracing.exporter が不正です: %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing.sample_ratio は0〜1を指定してください: %v", c.Tracing.SampleRatio))
+	}
+
+	if !slices.Contains(logLevels, c.Log.Level) {
+		errs = append(errs, fmt.Errorf("log.level が不正です: %q", c.Log.Level))
+	}
+	if c.Log.Format != "json" && c.Log.Format != "text" {
+		errs = append(errs, fmt.Errorf("log.format が不正です: %q", c.Log.Format))
+	}
+	for _, component := range slices.Sorted(maps.Keys(c.Log.Components)) {
+		if level := c.Log.Components[component]; !slices.Contains(logLevels, level) {
+			errs = append(errs, fmt.Errorf("log.components.%s が不正です: %q", component, level))
+		}
+	}
+
+	switch c.Storage.Backend {
+	case "local":
+		if c.Storage.Local.Dir == "" {
+			errs = append(errs, errors.New("storage.local.dir を指定してください"))
+		}
+		if _, err := url.Parse(c.Storage.Local.BaseURL); err != nil || c.Storage.Local.BaseURL == "" {
+			errs = append(errs, fmt.Errorf("storage.local.base_url が不正です: %q", c.Storage.Local.BaseURL))
+		}
+	case "s3":
+		if c.Storage.S3.Endpoint == "" || c.Storage.S3.Bucket == "" {
+			errs = append(errs, errors.New("storage.s3.endpoint / storage.s3.bucket を指定してください"))
+		}
+		if c.Storage.S3.BaseURL == "" && c.Storage.S3.PresignedExpiry <= 0 {
+			errs = append(errs, fmt.Errorf("storage.s3.presigned_expiry は正の値を指定してください: %s", c.Storage.S3.PresignedExpiry))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("storage.backend が不正です: %q", c.Storage.Backend))
+	}
+	if c.Storage.MaxUploadBytes <= 0 {
+		errs = append(errs, fmt.Errorf("storage.max_upload_bytes は正の値を指定してください: %d", c.Storage.MaxUploadBytes))
+	}
+
+	// 本番環境では開発用の設定を許可しない
+	if c.IsProduction() {
+		if c.Auth.HS256Secret != "" {
+			errs = append(errs, errors.New("本番環境では auth.hs256_secret を使用できません (JWKSを設定してください)"))
+		}
+		if slices.Contains(c.Server.CORSAllowedOrigins, "*") {
+			errs = append(errs, errors.New("本番環境では server.cors_allowed_origins に * を指定できません"))
+		}
+		// ローカルの保存先は記事の公開状態を確認せずに配信するため、開発環境専用
+		if c.Storage.Backend == "local" {
+			errs = append(errs, errors.New("本番環境では storage.backend に local を指定できません (s3を設定してください)"))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("❌ 設定が不正です: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// IsProduction 本番環境かどうか
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+// String シークレットを伏せたYAML形式で設定を返す (起動時のログ出力用)
+func (c *Config) String() string {
+	data, err := yaml.Marshal(c)
+	if err != nil {
+		return fmt.Sprintf("<設定の出力に失敗しました: %v>", err)
+	}
+	return string(data)
+}
+
+// DSN PostgreSQLの接続文字列
+func (c DatabaseConfig) DSN() string {
+	if c.URL != "" {
+		return c.URL.Value()
+	}
+	return fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%d sslmode=%s",
+		c.Host, c.User, c.Password.Value(), c.Name, c.Port, c.SSLMode)
+}
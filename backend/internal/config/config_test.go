@@ -0,0 +1,209 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Default().Validate() error = %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(c *Config)
+		wantErr string // 空の場合はエラーにならないこと
+	}{
+		{
+			name:    "unknown env",
+			modify:  func(c *Config) { c.Env = "staging" },
+			wantErr: "env は",
+		},
+		{
+			name:    "port out of range",
+			modify:  func(c *Config) { c.Server.Port = 70000 },
+			wantErr: "server.port",
+		},
+		{
+			name:    "database port is not checked when url is set",
+			modify:  func(c *Config) { c.Database.URL = "postgres://localhost/db"; c.Database.Port = 0 },
+			wantErr: "",
+		},
+		{
+			name:    "invalid sslmode",
+			modify:  func(c *Config) { c.Database.SSLMode = "on" },
+			wantErr: "database.sslmode",
+		},
+		{
+			name:    "negative rate limit",
+			modify:  func(c *Config) { c.RateLimit.Query.PerMinute = -1 },
+			wantErr: "rate_limit.query",
+		},
+		{
+			name:    "zero burst with rate limit",
+			modify:  func(c *Config) { c.RateLimit.Search.Burst = 0 },
+			wantErr: "rate_limit.search.burst",
+		},
+		{
+			name:    "rate limit disabled",
+			modify:  func(c *Config) { c.RateLimit.Search = RateLimitRule{} },
+			wantErr: "",
+		},
+		{
+			name:    "trusted forwarded for without proxies",
+			modify:  func(c *Config) { c.RateLimit.TrustForwardedFor = true; c.RateLimit.TrustedProxies = 0 },
+			wantErr: "rate_limit.trusted_proxies",
+		},
+		{
+			name:    "unknown log level for component",
+			modify:  func(c *Config) { c.Log.Components = map[string]string{"db": "verbose"} },
+			wantErr: "log.components.db",
+		},
+		{
+			name:    "s3 without bucket",
+			modify:  func(c *Config) { c.Storage.Backend = "s3"; c.Storage.S3.Endpoint = "minio:9000" },
+			wantErr: "storage.s3.bucket",
+		},
+		{
+			name: "hs256 secret in production",
+			modify: func(c *Config) {
+				c.Env = EnvProduction
+				c.Server.CORSAllowedOrigins = []string{"https://example.com"}
+				c.Auth.HS256Secret = "secret"
+			},
+			wantErr: "auth.hs256_secret",
+		},
+		{
+			name:    "wildcard cors in production",
+			modify:  func(c *Config) { c.Env = EnvProduction },
+			wantErr: "server.cors_allowed_origins",
+		},
+		{
+			name:    "local storage in production",
+			modify:  func(c *Config) { c.Env = EnvProduction; c.Server.CORSAllowedOrigins = []string{"https://example.com"} },
+			wantErr: "storage.backend",
+		},
+		{
+			name: "production",
+			modify: func(c *Config) {
+				c.Env = EnvProduction
+				c.Server.CORSAllowedOrigins = []string{"https://example.com"}
+				c.Storage.Backend = "s3"
+				c.Storage.S3.Endpoint = "s3.example.com"
+				c.Storage.S3.Bucket = "attachments"
+			},
+			wantErr: "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Default()
+			tt.modify(c)
+			err := c.Validate()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("Validate() error = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Validate() error = %v, want containing %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `
+server:
+  port: 9000
+  shutdown_timeout: 10s
+database:
+  host: db
+  password: from-file
+rate_limit:
+  search:
+    per_minute: 120
+    burst: 30
+`
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+	t.Setenv("SERVER_PORT", "9100")
+	t.Setenv("DB_PASSWORD", "from-env")
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://a.example.com, https://b.example.com")
+	t.Setenv("RATE_LIMIT_SEARCH_BURST", "40")
+	t.Setenv("LOG_COMPONENT_LEVELS", "db=debug,es=warn")
+	t.Setenv("SERVER_IDLE_TIMEOUT", "") // 空の場合は上書きしない
+
+	c, err := Load()
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	// 環境変数が設定ファイルより優先される
+	if c.Server.Port != 9100 {
+		t.Errorf("Server.Port = %d, want 9100", c.Server.Port)
+	}
+	if c.Database.Password.Value() != "from-env" {
+		t.Errorf("Database.Password = %q, want from-env", c.Database.Password.Value())
+	}
+	if c.RateLimit.Search != (RateLimitRule{PerMinute: 120, Burst: 40}) {
+		t.Errorf("RateLimit.Search = %+v, want {120 40}", c.RateLimit.Search)
+	}
+	// 設定ファイルの値
+	if c.Database.Host != "db" {
+		t.Errorf("Database.Host = %q, want db", c.Database.Host)
+	}
+	if c.Server.ShutdownTimeout != 10*time.Second {
+		t.Errorf("Server.ShutdownTimeout = %s, want 10s", c.Server.ShutdownTimeout)
+	}
+	// どちらにも記載のない項目はデフォルト値
+	if c.Server.IdleTimeout != Default().Server.IdleTimeout {
+		t.Errorf("Server.IdleTimeout = %s, want default", c.Server.IdleTimeout)
+	}
+	if got := strings.Join(c.Server.CORSAllowedOrigins, " "); got != "https://a.example.com https://b.example.com" {
+		t.Errorf("Server.CORSAllowedOrigins = %q", got)
+	}
+	if c.Log.Components["db"] != "debug" || c.Log.Components["es"] != "warn" {
+		t.Errorf("Log.Components = %v", c.Log.Components)
+	}
+}
+
+func TestLoadInvalidEnv(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("SERVER_PORT", "eighty")
+	t.Setenv("SERVER_READ_TIMEOUT", "5")
+	t.Setenv("LOG_COMPONENT_LEVELS", "debug")
+
+	_, err := Load()
+	if err == nil {
+		t.Fatal("Load() error = nil, want error")
+	}
+	// 変換エラーはまとめて返す
+	for _, key := range []string{"SERVER_PORT", "SERVER_READ_TIMEOUT", "LOG_COMPONENT_LEVELS"} {
+		if !strings.Contains(err.Error(), key) {
+			t.Errorf("Load() error = %v, want containing %s", err, key)
+		}
+	}
+}
+
+func TestLoadUnknownFileKey(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("server:\n  prot: 9000\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("CONFIG_FILE", path)
+
+	if _, err := Load(); err == nil {
+		t.Fatal("Load() error = nil, want error for unknown key")
+	}
+}
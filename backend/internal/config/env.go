@@ -0,0 +1,207 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// loadEnv 環境変数が設定されている項目を上書きする
+func (c *Config) loadEnv() error {
+	e := &envReader{}
+
+	e.string("SERVER_ENV", &c.Env)
+
+	e.int("SERVER_PORT", &c.Server.Port)
+	e.list("CORS_ALLOWED_ORIGINS", &c.Server.CORSAllowedOrigins)
+	e.bool("GRAPHQL_PLAYGROUND", &c.Server.Playground)
+	e.duration("SERVER_READ_HEADER_TIMEOUT", &c.Server.ReadHeaderTimeout)
+	e.duration("SERVER_READ_TIMEOUT", &c.Server.ReadTimeout)
+	e.duration("SERVER_WRITE_TIMEOUT", &c.Server.WriteTimeout)
+	e.duration("SERVER_IDLE_TIMEOUT", &c.Server.IdleTimeout)
+	e.duration("SERVER_SHUTDOWN_TIMEOUT", &c.Server.ShutdownTimeout)
+	e.int("SERVER_MAX_BODY_BYTES", &c.Server.MaxBodyBytes)
+
+	e.secret("DATABASE_URL", &c.Database.URL)
+	e.string("DB_HOST", &c.Database.Host)
+	e.int("DB_PORT", &c.Database.Port)
+	e.string("DB_USER", &c.Database.User)
+	e.secret("DB_PASSWORD", &c.Database.Password)
+	e.string("DB_NAME", &c.Database.Name)
+	e.string("DB_SSLMODE", &c.Database.SSLMode)
+	e.duration("DB_SLOW_QUERY_THRESHOLD", &c.Database.SlowQueryThreshold)
+
+	e.list("ES_HOST", &c.Elasticsearch.Addresses)
+	e.string("ES_CLOUD_ID", &c.Elasticsearch.CloudID)
+	e.secret("ES_API_KEY", &c.Elasticsearch.APIKey)
+	e.duration("ES_SLOW_REQUEST_THRESHOLD", &c.Elasticsearch.SlowRequestThreshold)
+	e.duration("ES_LIKE_COUNT_SYNC_INTERVAL", &c.Elasticsearch.LikeCountSyncInterval)
+
+	e.secret("AUTH_HS256_SECRET", &c.Auth.HS256Secret)
+	e.string("AUTH_JWKS_URL", &c.Auth.JWKSURL)
+	e.string("AUTH_JWKS_FILE", &c.Auth.JWKSFile)
+	e.string("AUTH_ISSUER", &c.Auth.Issuer)
+	e.string("AUTH_AUDIENCE", &c.Auth.Audience)
+
+	e.int("GRAPHQL_MAX_COMPLEXITY", &c.GraphQL.MaxComplexity)
+	e.int("GRAPHQL_MAX_DEPTH", &c.GraphQL.MaxDepth)
+	e.list("GRAPHQL_METRIC_OPERATIONS", &c.GraphQL.MetricOperations)
+
+	e.rule("RATE_LIMIT_SEARCH", &c.RateLimit.Search)
+	e.rule("RATE_LIMIT_MUTATION", &c.RateLimit.Mutation)
+	e.rule("RATE_LIMIT_QUERY", &c.RateLimit.Query)
+	e.rule("RATE_LIMIT_IP", &c.RateLimit.IP)
+	e.bool("RATE_LIMIT_TRUST_FORWARDED_FOR", &c.RateLimit.TrustForwardedFor)
+	e.int("RATE_LIMIT_TRUSTED_PROXIES", &c.RateLimit.TrustedProxies)
+
+	e.duration("SCHEDULER_INTERVAL", &c.Scheduler.Interval)
+
+	e.duration("TRASH_RETENTION", &c.Trash.Retention)
+	e.duration("TRASH_PURGE_INTERVAL", &c.Trash.PurgeInterval)
+
+	e.string("TRACING_EXPORTER", &c.Tracing.Exporter)
+	e.string("TRACING_OTLP_ENDPOINT", &c.Tracing.OTLPEndpoint)
+	e.bool("TRACING_OTLP_INSECURE", &c.Tracing.OTLPInsecure)
+	e.string("TRACING_SERVICE_NAME", &c.Tracing.ServiceName)
+	e.float("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+
+	e.string("LOG_LEVEL", &c.Log.Level)
+	e.string("LOG_FORMAT", &c.Log.Format)
+	e.levels("LOG_COMPONENT_LEVELS", &c.Log.Components)
+
+	e.string("STORAGE_BACKEND", &c.Storage.Backend)
+	e.int("STORAGE_MAX_UPLOAD_BYTES", &c.Storage.MaxUploadBytes)
+	e.string("STORAGE_LOCAL_DIR", &c.Storage.Local.Dir)
+	e.string("STORAGE_LOCAL_BASE_URL", &c.Storage.Local.BaseURL)
+	e.string("STORAGE_S3_ENDPOINT", &c.Storage.S3.Endpoint)
+	e.string("STORAGE_S3_REGION", &c.Storage.S3.Region)
+	e.string("STORAGE_S3_BUCKET", &c.Storage.S3.Bucket)
+	e.string("STORAGE_S3_ACCESS_KEY_ID", &c.Storage.S3.AccessKeyID)
+	e.secret("STORAGE_S3_SECRET_ACCESS_KEY", &c.Storage.S3.SecretAccessKey)
+	e.bool("STORAGE_S3_USE_SSL", &c.Storage.S3.UseSSL)
+	e.string("STORAGE_S3_BASE_URL", &c.Storage.S3.BaseURL)
+	e.duration("STORAGE_S3_PRESIGNED_EXPIRY", &c.Storage.S3.PresignedExpiry)
+
+	if len(e.errs) > 0 {
+		return fmt.Errorf("❌ 環境変数の形式が不正です: %w", errors.Join(e.errs...))
+	}
+	return nil
+}
+
+// envReader 環境変数を型に変換して読み込む (変換エラーはまとめて返す)
+type envReader struct {
+	errs []error
+}
+
+func (e *envReader) lookup(key string) (string, bool) {
+	v, ok := os.LookupEnv(key)
+	if !ok || v == "" {
+		return "", false
+	}
+	return v, true
+}
+
+func (e *envReader) string(key string, dst *string) {
+	if v, ok := e.lookup(key); ok {
+		*dst = v
+	}
+}
+
+func (e *envReader) secret(key string, dst *Secret) {
+	if v, ok := e.lookup(key); ok {
+		*dst = Secret(v)
+	}
+}
+
+// list カンマ区切りの値をリストとして読み込む
+func (e *envReader) list(key string, dst *[]string) {
+	v, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	*dst = list
+}
+
+func (e *envReader) int(key string, dst *int) {
+	v, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %w", key, err))
+		return
+	}
+	*dst = n
+}
+
+func (e *envReader) float(key string, dst *float64) {
+	v, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %w", key, err))
+		return
+	}
+	*dst = f
+}
+
+func (e *envReader) bool(key string, dst *bool) {
+	v, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %w", key, err))
+		return
+	}
+	*dst = b
+}
+
+func (e *envReader) duration(key string, dst *time.Duration) {
+	v, ok := e.lookup(key)
+	if !ok {
+		return
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		e.errs = append(e.errs, fmt.Errorf("%s: %w", key, err))
+		return
+	}
+	*dst = d
+}
+
+// rule 例: RATE_LIMIT_SEARCH_PER_MINUTE=60, RATE_LIMIT_SEARCH_BURST=20
+func (e *envReader) rule(prefix string, dst *RateLimitRule) {
+	e.int(prefix+"_PER_MINUTE", &dst.PerMinute)
+	e.int(prefix+"_BURST", &dst.Burst)
+}
+
+// levels 例: LOG_COMPONENT_LEVELS=db=debug,es=warn (設定ファイルの値に追加・上書きする)
+func (e *envReader) levels(key string, dst *map[string]string) {
+	var items []string
+	e.list(key, &items)
+	for _, item := range items {
+		component, level, ok := strings.Cut(item, "=")
+		if !ok || component == "" {
+			e.errs = append(e.errs, fmt.Errorf("%s: コンポーネント=レベル の形式で指定してください: %q", key, item))
+			continue
+		}
+		if *dst == nil {
+			*dst = map[string]string{}
+		}
+		(*dst)[component] = level
+	}
+}
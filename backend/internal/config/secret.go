@@ -0,0 +1,31 @@
+package config
+
+const redacted = "[REDACTED]"
+
+// Secret パスワードやAPIキーなど、ログに出力してはいけない設定値
+// fmtやYAMLで出力した場合は値が伏せられるため、実際の値はValueで取得する
+type Secret string
+
+// Value 実際の値を返す
+func (s Secret) Value() string {
+	return string(s)
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return `"` + s.String() + `"`
+}
+
+func (s Secret) MarshalYAML() (any, error) {
+	return s.String(), nil
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + s.String() + `"`), nil
+}
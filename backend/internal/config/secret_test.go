@@ -0,0 +1,76 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+const testSecret = "s3cr3t-value"
+
+func TestSecretRedaction(t *testing.T) {
+	s := Secret(testSecret)
+	if s.Value() != testSecret {
+		t.Fatalf("Value() = %q, want %q", s.Value(), testSecret)
+	}
+
+	jsonData, err := json.Marshal(struct{ Password Secret }{s})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outputs := map[string]string{
+		"%s":   fmt.Sprintf("%s", s),
+		"%v":   fmt.Sprintf("%v", s),
+		"%+v":  fmt.Sprintf("%+v", s),
+		"%#v":  fmt.Sprintf("%#v", s),
+		"json": string(jsonData),
+	}
+	for format, out := range outputs {
+		if strings.Contains(out, testSecret) {
+			t.Errorf("%s output leaks secret: %s", format, out)
+		}
+		if !strings.Contains(out, redacted) {
+			t.Errorf("%s output = %s, want containing %s", format, out, redacted)
+		}
+	}
+
+	// 未設定の場合は伏せずに空のまま出力する
+	if got := fmt.Sprint(Secret("")); got != "" {
+		t.Errorf("empty Secret = %q, want empty", got)
+	}
+}
+
+func TestConfigOutputRedactsSecrets(t *testing.T) {
+	c := Default()
+	c.Database.Password = testSecret
+	c.Database.URL = "postgres://user:" + testSecret + "@db/app"
+	c.Elasticsearch.APIKey = testSecret
+	c.Auth.HS256Secret = testSecret
+	c.Storage.S3.SecretAccessKey = testSecret
+
+	var text, jsonLog bytes.Buffer
+	slog.New(slog.NewTextHandler(&text, nil)).Info("config", "config", c.String(), "database", c.Database)
+	slog.New(slog.NewJSONHandler(&jsonLog, nil)).Info("config", "config", c.String(), "database", c.Database)
+
+	outputs := map[string]string{
+		"String":    c.String(),
+		"%v":        fmt.Sprintf("%v", *c),
+		"%+v":       fmt.Sprintf("%+v", *c),
+		"slog text": text.String(),
+		"slog json": jsonLog.String(),
+	}
+	for name, out := range outputs {
+		if strings.Contains(out, testSecret) {
+			t.Errorf("%s output leaks secret: %s", name, out)
+		}
+	}
+
+	// DSNには実際の値を使う
+	if dsn := c.Database.DSN(); !strings.Contains(dsn, testSecret) {
+		t.Errorf("DSN() = %q, want actual secret", dsn)
+	}
+}
@@ -0,0 +1,111 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrNotFound 一括取得の結果に指定したキーが含まれていない場合のエラー
+var ErrNotFound = errors.New("not found")
+
+// BatchFunc 複数のキーに対応する値をまとめて取得する関数
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader 一定時間内に要求されたキーをまとめて取得し、結果をキャッシュするローダー
+// キャッシュはLoaderの生存期間に限られるため、リクエストごとに生成して使う
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu    sync.Mutex
+	cache map[K]*result[V]
+	batch *batch[K, V]
+}
+
+type result[V any] struct {
+	value V
+	err   error
+	done  chan struct{}
+}
+
+type batch[K comparable, V any] struct {
+	keys    []K
+	results []*result[V]
+	once    sync.Once
+}
+
+func NewLoader[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    map[K]*result[V]{},
+	}
+}
+
+// Load キーに対応する値を取得する(同じバッチ内の他のキーとまとめて取得される)
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	r, ok := l.cache[key]
+	if !ok {
+		r = &result[V]{done: make(chan struct{})}
+		l.cache[key] = r
+		l.enqueue(ctx, key, r)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-r.done:
+		return r.value, r.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// enqueue 現在のバッチにキーを追加する(呼び出し側でロックを取得していること)
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, r *result[V]) {
+	if l.batch == nil {
+		b := &batch[K, V]{}
+		l.batch = b
+		time.AfterFunc(l.wait, func() { l.dispatch(ctx, b) })
+	}
+
+	b := l.batch
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, r)
+
+	// 上限に達したバッチは待たずに実行
+	if l.maxBatch > 0 && len(b.keys) >= l.maxBatch {
+		l.batch = nil
+		go l.dispatch(ctx, b)
+	}
+}
+
+// dispatch バッチをまとめて取得し、各キーの結果を通知する
+func (l *Loader[K, V]) dispatch(ctx context.Context, b *batch[K, V]) {
+	b.once.Do(func() {
+		l.mu.Lock()
+		if l.batch == b {
+			l.batch = nil
+		}
+		l.mu.Unlock()
+
+		values, err := l.fetch(ctx, b.keys)
+		for i, key := range b.keys {
+			r := b.results[i]
+			if err != nil {
+				r.err = err
+			} else if value, ok := values[key]; ok {
+				r.value = value
+			} else {
+				r.err = fmt.Errorf("%w: %v", ErrNotFound, key)
+			}
+			close(r.done)
+		}
+	})
+}
@@ -0,0 +1,168 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recorder 一括取得で要求されたキーを記録する
+type recorder struct {
+	mu      sync.Mutex
+	batches [][]int
+}
+
+func (r *recorder) fetch(err error) BatchFunc[int, string] {
+	return func(ctx context.Context, keys []int) (map[int]string, error) {
+		r.mu.Lock()
+		r.batches = append(r.batches, slices.Sorted(slices.Values(keys)))
+		r.mu.Unlock()
+
+		if err != nil {
+			return nil, err
+		}
+		values := make(map[int]string, len(keys))
+		for _, key := range keys {
+			// 負のキーは存在しないものとして扱う
+			if key >= 0 {
+				values[key] = fmt.Sprintf("v%d", key)
+			}
+		}
+		return values, nil
+	}
+}
+
+func (r *recorder) calls() [][]int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.batches)
+}
+
+type loadResult struct {
+	value string
+	err   error
+}
+
+// loadAll すべてのキーを並行してLoadし、キーと同じ順序で結果を返す
+func loadAll(ctx context.Context, l *Loader[int, string], keys []int) []loadResult {
+	results := make([]loadResult, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := l.Load(ctx, key)
+			results[i] = loadResult{value, err}
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+func TestLoaderBatchesConcurrentLoads(t *testing.T) {
+	r := &recorder{}
+	l := NewLoader(r.fetch(nil), 20*time.Millisecond, 0)
+
+	keys := []int{1, 2, 3, 4, 5}
+	results := loadAll(context.Background(), l, keys)
+
+	for i, key := range keys {
+		if results[i].err != nil || results[i].value != fmt.Sprintf("v%d", key) {
+			t.Errorf("Load(%d) = %q, %v", key, results[i].value, results[i].err)
+		}
+	}
+	if calls := r.calls(); len(calls) != 1 || !slices.Equal(calls[0], keys) {
+		t.Errorf("batches = %v, want [%v]", calls, keys)
+	}
+}
+
+func TestLoaderMaxBatch(t *testing.T) {
+	r := &recorder{}
+	l := NewLoader(r.fetch(nil), 20*time.Millisecond, 2)
+
+	loadAll(context.Background(), l, []int{1, 2, 3, 4, 5})
+
+	calls := r.calls()
+	var total int
+	for _, batch := range calls {
+		if len(batch) > 2 {
+			t.Errorf("batch %v exceeds max batch size", batch)
+		}
+		total += len(batch)
+	}
+	if total != 5 {
+		t.Errorf("batches = %v, want 5 keys in total", calls)
+	}
+}
+
+func TestLoaderDeduplicatesAndCaches(t *testing.T) {
+	r := &recorder{}
+	l := NewLoader(r.fetch(nil), 20*time.Millisecond, 0)
+
+	results := loadAll(context.Background(), l, []int{7, 7, 8, 7, 8})
+	for _, result := range results {
+		if result.err != nil {
+			t.Fatalf("Load() error = %v", result.err)
+		}
+	}
+	if calls := r.calls(); len(calls) != 1 || !slices.Equal(calls[0], []int{7, 8}) {
+		t.Fatalf("batches = %v, want [[7 8]]", calls)
+	}
+
+	// 取得済みのキーはキャッシュから返す
+	if value, err := l.Load(context.Background(), 7); err != nil || value != "v7" {
+		t.Errorf("cached Load(7) = %q, %v", value, err)
+	}
+	if calls := r.calls(); len(calls) != 1 {
+		t.Errorf("batches after cached load = %v, want 1 batch", calls)
+	}
+}
+
+func TestLoaderErrors(t *testing.T) {
+	t.Run("missing key", func(t *testing.T) {
+		r := &recorder{}
+		l := NewLoader(r.fetch(nil), 20*time.Millisecond, 0)
+
+		results := loadAll(context.Background(), l, []int{1, -1, 2})
+		if !errors.Is(results[1].err, ErrNotFound) {
+			t.Errorf("Load(-1) error = %v, want ErrNotFound", results[1].err)
+		}
+		// 他のキーには影響しない
+		for _, i := range []int{0, 2} {
+			if results[i].err != nil {
+				t.Errorf("Load() error = %v, want nil", results[i].err)
+			}
+		}
+	})
+
+	t.Run("batch error", func(t *testing.T) {
+		errFetch := errors.New("fetch failed")
+		r := &recorder{}
+		l := NewLoader(r.fetch(errFetch), 20*time.Millisecond, 0)
+
+		for i, result := range loadAll(context.Background(), l, []int{1, 2, 3}) {
+			if !errors.Is(result.err, errFetch) {
+				t.Errorf("result %d error = %v, want %v", i, result.err, errFetch)
+			}
+		}
+	})
+}
+
+func TestLoaderContextCanceled(t *testing.T) {
+	block := make(chan struct{})
+	defer close(block)
+	l := NewLoader(func(ctx context.Context, keys []int) (map[int]string, error) {
+		<-block
+		return nil, nil
+	}, time.Millisecond, 0)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := l.Load(ctx, 1); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Load() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
@@ -0,0 +1,89 @@
+package apperror
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Code クライアントに返すエラーの種類(GraphQLのextensions.codeに設定される)
+type Code string
+
+const (
+	CodeNotFound        Code = "NOT_FOUND"
+	CodeUnauthenticated Code = "UNAUTHENTICATED"
+	CodeForbidden       Code = "FORBIDDEN"
+	CodeValidation      Code = "VALIDATION"
+	CodeConflict        Code = "CONFLICT"
+	CodeUnavailable     Code = "UNAVAILABLE"
+	CodeRateLimited     Code = "RATE_LIMITED"
+	CodeInternal        Code = "INTERNAL"
+)
+
+// Error コード付きのドメインエラー
+// Messageはクライアントに返してよい内容、Errは内部の原因(ログ出力のみ)
+type Error struct {
+	Code    Code
+	Message string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%s: %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+func New(code Code, message string) *Error {
+	return &Error{Code: code, Message: message}
+}
+
+func Wrap(code Code, message string, err error) *Error {
+	return &Error{Code: code, Message: message, Err: err}
+}
+
+func NotFound(message string) *Error {
+	return New(CodeNotFound, message)
+}
+
+func Unauthenticated(message string) *Error {
+	return New(CodeUnauthenticated, message)
+}
+
+func Forbidden(message string) *Error {
+	return New(CodeForbidden, message)
+}
+
+func Validation(message string) *Error {
+	return New(CodeValidation, message)
+}
+
+func Conflict(message string) *Error {
+	return New(CodeConflict, message)
+}
+
+func Unavailable(message string, err error) *Error {
+	return Wrap(CodeUnavailable, message, err)
+}
+
+func Internal(err error) *Error {
+	return Wrap(CodeInternal, "internal server error", err)
+}
+
+// CodeOf エラーのコードを取得する(コードが付いていない場合はINTERNAL)
+func CodeOf(err error) Code {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return CodeInternal
+}
+
+// Is エラーが指定したコードを持つか判定する
+func Is(err error, code Code) bool {
+	return CodeOf(err) == code
+}
@@ -0,0 +1,31 @@
+package event
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/domain/model"
+)
+
+type ArticleEventType string
+
+const (
+	ArticleCreated   ArticleEventType = "created"
+	ArticleUpdated   ArticleEventType = "updated"
+	ArticlePublished ArticleEventType = "published"
+	ArticleArchived  ArticleEventType = "archived"
+)
+
+// ArticleEvent 記事のライフサイクルイベント
+type ArticleEvent struct {
+	Type    ArticleEventType
+	Article model.Article
+}
+
+type ArticleEventPublisher interface {
+	// 記事イベントを購読者に配信する
+	Publish(ctx context.Context, event ArticleEvent)
+}
+
+type ArticleEventSubscriber interface {
+	// ctxがキャンセルされるまで記事イベントを受け取る
+	Subscribe(ctx context.Context) <-chan ArticleEvent
+}
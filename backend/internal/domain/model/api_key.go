@@ -0,0 +1,44 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// APIキーに付与できるスコープ
+const (
+	ScopeArticlesRead  = "articles:read"
+	ScopeArticlesWrite = "articles:write"
+	ScopeSearchAdmin   = "search:admin"
+)
+
+// APIKey 機械クライアント(バッチ処理など)用のAPIキー
+// シークレットはハッシュ化して保存し、平文は発行時にのみ返す
+type APIKey struct {
+	gorm.Model
+	UserID     uint       `gorm:"not null;index:idx_user_on_api_keys"`
+	Name       string     `gorm:"not null;size:255"`
+	Prefix     string     `gorm:"not null;uniqueIndex:idx_prefix_on_api_keys"` // キーの識別子(平文で保存)
+	SecretHash string     `gorm:"not null"`                                    // シークレットのSHA-256
+	Scopes     []string   `gorm:"not null;serializer:json"`
+	ExpiresAt  *time.Time // nilの場合は無期限
+	LastUsedAt *time.Time
+	RevokedAt  *time.Time
+
+	Owner User `gorm:"constraint:OnDelete:CASCADE;foreignKey:UserID"`
+}
+
+// HasScope: 指定したスコープが付与されているか
+func (k *APIKey) HasScope(scope string) bool {
+	return slices.Contains(k.Scopes, scope)
+}
+
+// IsActive: 失効・期限切れでないか
+func (k *APIKey) IsActive(now time.Time) bool {
+	if k.RevokedAt != nil {
+		return false
+	}
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
@@ -1,15 +1,33 @@
 package model
 
 import (
+	"time"
+
 	"gorm.io/gorm"
 )
 
 type Article struct {
 	gorm.Model
-	UserID  uint   `gorm:"not null;index:idx_user_on_articles"`
-	Title   string `gorm:"not null;size:255"`
-	Content string
-	Status  string `gorm:"not null;default:draft"` // draft, published, archived
+	UserID    uint   `gorm:"not null;index:idx_user_on_articles"`
+	Title     string `gorm:"not null;size:255"`
+	Slug      string `gorm:"not null;default:''"` // 現在のスラッグ(過去のスラッグを含めてArticleSlugにも保存する)
+	Content   string
+	Status    string     `gorm:"not null;default:draft"`           // draft, published, archived
+	PublishAt *time.Time `gorm:"index:idx_publish_at_on_articles"` // 予約公開日時(未設定の場合はnil)
+	ClaimedAt *time.Time // 予約公開ワーカーが公開処理を始めた日時(一定時間を過ぎると他のワーカーが処理し直す)
+
+	LikeCount        int `gorm:"not null;default:0"` // いいね数(いいねの追加・取り消しと同じトランザクションで更新する)
+	IndexedLikeCount int `gorm:"not null;default:0"` // 検索エンジンに反映済みのいいね数
+
+	Author User  `gorm:"constraint:OnDelete:CASCADE;foreignKey:UserID"`
+	Tags   []Tag `gorm:"many2many:article_tags;constraint:OnDelete:CASCADE"`
+}
 
-	Author User `gorm:"constraint:OnDelete:CASCADE;foreignKey:UserID"`
+// TagNames: タグ名の一覧
+func (a *Article) TagNames() []string {
+	names := make([]string, 0, len(a.Tags))
+	for _, tag := range a.Tags {
+		names = append(names, tag.Name)
+	}
+	return names
 }
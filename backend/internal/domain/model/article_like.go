@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// ArticleLike 記事への「いいね」(ユーザーごとに1記事1件)
+type ArticleLike struct {
+	ID        uint `gorm:"primarykey"`
+	ArticleID uint `gorm:"not null;uniqueIndex:idx_article_user_on_article_likes,priority:1"`
+	UserID    uint `gorm:"not null;uniqueIndex:idx_article_user_on_article_likes,priority:2"`
+	CreatedAt time.Time
+
+	Article Article `gorm:"constraint:OnDelete:CASCADE;foreignKey:ArticleID"`
+	User    User    `gorm:"constraint:OnDelete:CASCADE;foreignKey:UserID"`
+}
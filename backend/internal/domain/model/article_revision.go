@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// ArticleRevision 記事の更新履歴 (作成・更新ごとに更新後の内容を保存する)
+type ArticleRevision struct {
+	ID        uint   `gorm:"primarykey"`
+	ArticleID uint   `gorm:"not null;uniqueIndex:idx_article_version_on_article_revisions,priority:1"`
+	Version   int    `gorm:"not null;uniqueIndex:idx_article_version_on_article_revisions,priority:2"` // 記事ごとの連番(1から)
+	Title     string `gorm:"not null;size:255"`
+	Content   string
+	Status    string `gorm:"not null"`
+	EditorID  *uint  // 予約公開などシステムによる更新の場合はnil
+	CreatedAt time.Time
+
+	Article Article `gorm:"constraint:OnDelete:CASCADE;foreignKey:ArticleID"`
+	Editor  *User   `gorm:"constraint:OnDelete:SET NULL;foreignKey:EditorID"`
+}
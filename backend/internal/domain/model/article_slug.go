@@ -0,0 +1,48 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gosimple/slug"
+)
+
+const (
+	maxSlugLength = 80        // スラッグの文字数の上限(連番を付ける前)
+	fallbackSlug  = "article" // タイトルから英数字を取り出せない場合のスラッグ
+)
+
+// ArticleSlug 記事のURLに使うスラッグ (現在のスラッグと、タイトル変更前の古いスラッグを保存する)
+// スラッグは作成者ごとに一意で、古いスラッグも他の記事には使わせない
+type ArticleSlug struct {
+	ID        uint   `gorm:"primarykey"`
+	ArticleID uint   `gorm:"not null;index:idx_article_on_article_slugs"`
+	UserID    uint   `gorm:"not null;uniqueIndex:idx_user_slug_on_article_slugs,priority:1"`
+	Slug      string `gorm:"not null;size:96;uniqueIndex:idx_user_slug_on_article_slugs,priority:2"`
+	CreatedAt time.Time
+
+	Article Article `gorm:"constraint:OnDelete:CASCADE;foreignKey:ArticleID"`
+	Author  User    `gorm:"constraint:OnDelete:CASCADE;foreignKey:UserID"`
+}
+
+// SlugFromTitle: タイトルからスラッグを作成する(日本語などはローマ字に変換する)
+func SlugFromTitle(title string) string {
+	s := slug.Make(title)
+	if len(s) > maxSlugLength {
+		s = strings.TrimRight(s[:maxSlugLength], "-")
+	}
+	if s == "" {
+		return fallbackSlug
+	}
+	return s
+}
+
+// UniqueSlug: 使用済みの場合は連番を付けて、使われていないスラッグを返す
+func UniqueSlug(base string, taken func(slug string) bool) string {
+	candidate := base
+	for i := 2; taken(candidate); i++ {
+		candidate = fmt.Sprintf("%s-%d", base, i)
+	}
+	return candidate
+}
@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// Attachment 記事に添付したファイル (ファイル本体はBlobStoreに保存する)
+type Attachment struct {
+	ID          uint   `gorm:"primarykey"`
+	ArticleID   uint   `gorm:"not null;index:idx_article_on_attachments"`
+	UserID      uint   `gorm:"not null"`                                             // アップロードしたユーザー
+	Key         string `gorm:"not null;size:255;uniqueIndex:idx_key_on_attachments"` // BlobStore上のキー
+	Filename    string `gorm:"not null;size:255"`                                    // アップロード時のファイル名
+	ContentType string `gorm:"not null;size:127"`
+	Size        int64  `gorm:"not null"`
+	CreatedAt   time.Time
+
+	Article Article `gorm:"constraint:OnDelete:CASCADE;foreignKey:ArticleID"`
+	Owner   User    `gorm:"constraint:OnDelete:CASCADE;foreignKey:UserID"`
+}
@@ -0,0 +1,14 @@
+package model
+
+import "time"
+
+// Bookmark ユーザーが後で読むために保存した記事(本人のみ閲覧できる)
+type Bookmark struct {
+	ID        uint `gorm:"primarykey"`
+	UserID    uint `gorm:"not null;uniqueIndex:idx_user_article_on_bookmarks,priority:1"`
+	ArticleID uint `gorm:"not null;uniqueIndex:idx_user_article_on_bookmarks,priority:2"`
+	CreatedAt time.Time
+
+	User    User    `gorm:"constraint:OnDelete:CASCADE;foreignKey:UserID"`
+	Article Article `gorm:"constraint:OnDelete:CASCADE;foreignKey:ArticleID"`
+}
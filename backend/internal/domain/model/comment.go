@@ -0,0 +1,26 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// コメントのモデレーション状態
+const (
+	CommentStatusPending  = "pending"  // 記事の作成者の承認待ち
+	CommentStatusApproved = "approved" // 誰でも閲覧可能
+	CommentStatusHidden   = "hidden"   // 記事の作成者が非表示にした
+)
+
+// Comment 記事へのコメント (ParentIDが設定されている場合は他のコメントへの返信)
+type Comment struct {
+	gorm.Model
+	ArticleID uint   `gorm:"not null;index:idx_article_parent_on_comments,priority:1"`
+	ParentID  *uint  `gorm:"index:idx_article_parent_on_comments,priority:2"`
+	UserID    uint   `gorm:"not null;index:idx_user_on_comments"`
+	Body      string `gorm:"not null"`
+	Status    string `gorm:"not null;default:pending"` // pending, approved, hidden
+
+	Article Article  `gorm:"constraint:OnDelete:CASCADE;foreignKey:ArticleID"`
+	Parent  *Comment `gorm:"constraint:OnDelete:CASCADE;foreignKey:ParentID"`
+	Author  User     `gorm:"constraint:OnDelete:CASCADE;foreignKey:UserID"`
+}
@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+// Tag 記事の分類に使うタグ (名前は正規化して保存する)
+type Tag struct {
+	ID        uint   `gorm:"primarykey"`
+	Name      string `gorm:"not null;size:64;uniqueIndex:idx_name_on_tags"`
+	CreatedAt time.Time
+}
+
+// TagCount タグと公開記事数 (検索エンジンの集計結果)
+type TagCount struct {
+	Name  string
+	Count int64
+}
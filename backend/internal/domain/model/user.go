@@ -6,5 +6,8 @@ import (
 
 type User struct {
 	gorm.Model
-	UID string `gorm:"unique;not null"`
+	UID         string `gorm:"unique;not null"`
+	DisplayName string `gorm:"not null;default:''"`    // 認証トークンのnameクレーム
+	Email       string `gorm:"not null;default:''"`    // 認証トークンのemailクレーム
+	IsAdmin     bool   `gorm:"not null;default:false"` // APIキーの管理などを行える管理者
 }
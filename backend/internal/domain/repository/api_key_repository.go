@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/domain/model"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type APIKeyRepository interface {
+	GetAPIKeyByID(ctx context.Context, id uint) (*model.APIKey, error)
+	GetAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error)
+	ListAPIKeys(ctx context.Context) ([]*model.APIKey, error)
+
+	CreateAPIKey(ctx context.Context, apiKey *model.APIKey) error
+	UpdateAPIKey(ctx context.Context, apiKey *model.APIKey) error
+	TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error
+}
+
+type apiKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewAPIKeyRepository(db *gorm.DB) APIKeyRepository {
+	return &apiKeyRepository{db: db}
+}
+
+func (r *apiKeyRepository) GetAPIKeyByID(ctx context.Context, id uint) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	if err := conn(ctx, r.db).First(&apiKey, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("api key not found")
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+// GetAPIKeyByPrefix 識別子でAPIキーを取得する(所有者も合わせて取得)
+func (r *apiKeyRepository) GetAPIKeyByPrefix(ctx context.Context, prefix string) (*model.APIKey, error) {
+	var apiKey model.APIKey
+	if err := conn(ctx, r.db).Preload("Owner").Where("prefix = ?", prefix).First(&apiKey).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("api key not found")
+		}
+		return nil, err
+	}
+	return &apiKey, nil
+}
+
+func (r *apiKeyRepository) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	var apiKeys []*model.APIKey
+	if err := conn(ctx, r.db).Order("id DESC").Find(&apiKeys).Error; err != nil {
+		return nil, err
+	}
+	return apiKeys, nil
+}
+
+func (r *apiKeyRepository) CreateAPIKey(ctx context.Context, apiKey *model.APIKey) error {
+	return conn(ctx, r.db).Create(apiKey).Error
+}
+
+func (r *apiKeyRepository) UpdateAPIKey(ctx context.Context, apiKey *model.APIKey) error {
+	return conn(ctx, r.db).Omit("Owner").Save(apiKey).Error
+}
+
+// TouchAPIKey 最終利用日時を更新する(updated_atは変更しない)
+func (r *apiKeyRepository) TouchAPIKey(ctx context.Context, id uint, usedAt time.Time) error {
+	return conn(ctx, r.db).Model(&model.APIKey{}).Where("id = ?", id).UpdateColumn("last_used_at", usedAt).Error
+}
@@ -2,18 +2,43 @@ package repository
 
 import (
 	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
 	"elasticsearch-sample/backend/internal/domain/model"
+	"errors"
+	"fmt"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ArticleRepository interface {
 	GetArticleByID(ctx context.Context, id int64) (*model.Article, error)
+	GetArticleBySlug(ctx context.Context, userID uint, slug string) (*model.Article, error)
 	ListArticles(ctx context.Context, page, pageSize int) ([]*model.Article, error)
+	ListVisibleArticles(ctx context.Context, viewerID uint, page, pageSize int) ([]*model.Article, error)
+	ListArticlesByUser(ctx context.Context, userID uint, statuses []string, afterID uint, limit int) ([]*model.Article, error)
+	CountArticlesByUser(ctx context.Context, userID uint, statuses []string) (int64, error)
 
 	CreateArticle(ctx context.Context, article *model.Article) (*model.Article, error)
 	UpdateArticle(ctx context.Context, article *model.Article) (*model.Article, error)
 	DeleteArticle(ctx context.Context, id int64) error
+
+	GetDeletedArticleByID(ctx context.Context, id int64) (*model.Article, error)
+	ListDeletedArticlesByUser(ctx context.Context, userID uint, afterID uint, limit int) ([]*model.Article, error)
+	CountDeletedArticlesByUser(ctx context.Context, userID uint) (int64, error)
+	ListArticlesDeletedBefore(ctx context.Context, deletedBefore time.Time, limit int) ([]*model.Article, error)
+	RestoreArticle(ctx context.Context, id int64) error
+	PurgeArticle(ctx context.Context, id int64) error
+
+	FindOrCreateTags(ctx context.Context, names []string) ([]model.Tag, error)
+	ReplaceArticleTags(ctx context.Context, article *model.Article, tags []model.Tag) error
+
+	ListArticleSlugs(ctx context.Context, userID uint, base string) ([]model.ArticleSlug, error)
+
+	ClaimDueScheduledArticles(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.Article, error)
+	PublishScheduledArticle(ctx context.Context, article *model.Article, now time.Time) (bool, error)
+	ReleaseScheduledArticle(ctx context.Context, id uint) error
 }
 
 type articleRepository struct {
@@ -26,7 +51,26 @@ func NewArticleRepository(db *gorm.DB) ArticleRepository {
 
 func (r *articleRepository) GetArticleByID(ctx context.Context, id int64) (*model.Article, error) {
 	var article model.Article
-	if err := r.db.WithContext(ctx).First(&article, id).Error; err != nil {
+	if err := conn(ctx, r.db).Preload("Tags").First(&article, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("article not found")
+		}
+		return nil, err
+	}
+	return &article, nil
+}
+
+// GetArticleBySlug 作成者とスラッグ(過去のスラッグを含む)で記事を取得する
+func (r *articleRepository) GetArticleBySlug(ctx context.Context, userID uint, slug string) (*model.Article, error) {
+	var article model.Article
+	if err := conn(ctx, r.db).
+		Joins("JOIN article_slugs ON article_slugs.article_id = articles.id").
+		Where("article_slugs.user_id = ? AND article_slugs.slug = ?", userID, slug).
+		Preload("Tags").
+		First(&article).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("article not found")
+		}
 		return nil, err
 	}
 	return &article, nil
@@ -35,26 +79,308 @@ func (r *articleRepository) GetArticleByID(ctx context.Context, id int64) (*mode
 func (r *articleRepository) ListArticles(ctx context.Context, page, pageSize int) ([]*model.Article, error) {
 	var articles []*model.Article
 	offset := (page - 1) * pageSize
-	if err := r.db.WithContext(ctx).Limit(pageSize).Offset(offset).Find(&articles).Error; err != nil {
+	if err := conn(ctx, r.db).Preload("Tags").Limit(pageSize).Offset(offset).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// ListVisibleArticles 公開中の記事と、閲覧者自身の記事を取得する(viewerIDが0の場合は公開中の記事のみ)
+func (r *articleRepository) ListVisibleArticles(ctx context.Context, viewerID uint, page, pageSize int) ([]*model.Article, error) {
+	var articles []*model.Article
+	offset := (page - 1) * pageSize
+	query := conn(ctx, r.db)
+	if viewerID != 0 {
+		query = query.Where("status = ? OR user_id = ?", "published", viewerID)
+	} else {
+		query = query.Where("status = ?", "published")
+	}
+	if err := query.Preload("Tags").Order("id").Limit(pageSize).Offset(offset).Find(&articles).Error; err != nil {
 		return nil, err
 	}
 	return articles, nil
 }
 
+// ListArticlesByUser ユーザーの記事を新しい順に取得する(afterIDより前の記事のみ)
+func (r *articleRepository) ListArticlesByUser(ctx context.Context, userID uint, statuses []string, afterID uint, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	query := r.scopeArticlesByUser(ctx, userID, statuses)
+	if afterID > 0 {
+		query = query.Where("id < ?", afterID)
+	}
+	if err := query.Preload("Tags").Order("id DESC").Limit(limit).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// CountArticlesByUser ユーザーの記事数を取得する
+func (r *articleRepository) CountArticlesByUser(ctx context.Context, userID uint, statuses []string) (int64, error) {
+	var count int64
+	if err := r.scopeArticlesByUser(ctx, userID, statuses).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *articleRepository) scopeArticlesByUser(ctx context.Context, userID uint, statuses []string) *gorm.DB {
+	query := conn(ctx, r.db).Model(&model.Article{}).Where("user_id = ?", userID)
+	if len(statuses) > 0 {
+		query = query.Where("status IN ?", statuses)
+	}
+	return query
+}
+
+// CreateArticle 記事を作成する(Tagsは作成済みのタグを関連付ける)
 func (r *articleRepository) CreateArticle(ctx context.Context, article *model.Article) (*model.Article, error) {
-	if err := r.db.WithContext(ctx).Create(article).Error; err != nil {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Tags.*").Create(article).Error; err != nil {
+			return err
+		}
+		return saveArticleSlug(tx, article)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return article, nil
 }
 
+// UpdateArticle 記事を更新する(タグの変更はReplaceArticleTagsで行う)
 func (r *articleRepository) UpdateArticle(ctx context.Context, article *model.Article) (*model.Article, error) {
-	if err := r.db.WithContext(ctx).Save(article).Error; err != nil {
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// いいね数と予約公開の処理状況は記事の更新と関係なく変わるため、上書きしない
+		if err := tx.Omit(clause.Associations, "LikeCount", "IndexedLikeCount", "ClaimedAt").Save(article).Error; err != nil {
+			return err
+		}
+		return saveArticleSlug(tx, article)
+	})
+	if err != nil {
 		return nil, err
 	}
 	return article, nil
 }
 
+// saveArticleSlug 記事の現在のスラッグを保存する
+// 以前に使っていたスラッグに戻した場合は既存の行をそのまま使い、他の記事が使っている場合はエラーにする
+func saveArticleSlug(tx *gorm.DB, article *model.Article) error {
+	if article.Slug == "" {
+		return nil
+	}
+	if err := tx.
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}, {Name: "slug"}}, DoNothing: true}).
+		Create(&model.ArticleSlug{ArticleID: article.ID, UserID: article.UserID, Slug: article.Slug}).Error; err != nil {
+		return err
+	}
+
+	var owner model.ArticleSlug
+	if err := tx.Where("user_id = ? AND slug = ?", article.UserID, article.Slug).First(&owner).Error; err != nil {
+		return err
+	}
+	if owner.ArticleID != article.ID {
+		return apperror.Conflict(fmt.Sprintf("slug is already used by another article: %s", article.Slug))
+	}
+	return nil
+}
+
+// DeleteArticle 記事を論理削除する(ゴミ箱に移動する)
 func (r *articleRepository) DeleteArticle(ctx context.Context, id int64) error {
-	return r.db.WithContext(ctx).Delete(&model.Article{}, id).Error
+	result := conn(ctx, r.db).Delete(&model.Article{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("article not found")
+	}
+	return nil
+}
+
+// GetDeletedArticleByID 論理削除された記事を取得する
+func (r *articleRepository) GetDeletedArticleByID(ctx context.Context, id int64) (*model.Article, error) {
+	var article model.Article
+	if err := r.scopeDeletedArticles(ctx).Preload("Tags").First(&article, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("deleted article not found")
+		}
+		return nil, err
+	}
+	return &article, nil
+}
+
+// ListDeletedArticlesByUser ユーザーの論理削除された記事を新しい順に取得する(afterIDより前の記事のみ)
+func (r *articleRepository) ListDeletedArticlesByUser(ctx context.Context, userID uint, afterID uint, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	query := r.scopeDeletedArticles(ctx).Where("user_id = ?", userID)
+	if afterID > 0 {
+		query = query.Where("id < ?", afterID)
+	}
+	if err := query.Preload("Tags").Order("id DESC").Limit(limit).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// CountDeletedArticlesByUser ユーザーの論理削除された記事数を取得する
+func (r *articleRepository) CountDeletedArticlesByUser(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.scopeDeletedArticles(ctx).Where("user_id = ?", userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// ListArticlesDeletedBefore 指定日時より前に論理削除された記事を古い順に取得する
+func (r *articleRepository) ListArticlesDeletedBefore(ctx context.Context, deletedBefore time.Time, limit int) ([]*model.Article, error) {
+	var articles []*model.Article
+	if err := r.scopeDeletedArticles(ctx).
+		Where("deleted_at < ?", deletedBefore).
+		Order("deleted_at ASC").
+		Limit(limit).
+		Find(&articles).Error; err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// RestoreArticle 論理削除された記事を元に戻す
+func (r *articleRepository) RestoreArticle(ctx context.Context, id int64) error {
+	result := r.scopeDeletedArticles(ctx).Where("id = ?", id).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("deleted article not found")
+	}
+	return nil
+}
+
+// PurgeArticle 論理削除された記事を物理削除する
+// 履歴・コメント・スラッグ・添付ファイルなどの関連する行は外部キーの制約で削除される
+func (r *articleRepository) PurgeArticle(ctx context.Context, id int64) error {
+	result := r.scopeDeletedArticles(ctx).Delete(&model.Article{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return apperror.NotFound("deleted article not found")
+	}
+	return nil
+}
+
+func (r *articleRepository) scopeDeletedArticles(ctx context.Context) *gorm.DB {
+	return conn(ctx, r.db).Unscoped().Model(&model.Article{}).Where("deleted_at IS NOT NULL")
+}
+
+// FindOrCreateTags 名前に対応するタグを取得する(存在しないタグは作成する)
+func (r *articleRepository) FindOrCreateTags(ctx context.Context, names []string) ([]model.Tag, error) {
+	if len(names) == 0 {
+		return []model.Tag{}, nil
+	}
+
+	tags := make([]model.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, model.Tag{Name: name})
+	}
+	// 同時に同じタグが作成されても失敗しないよう、既存のタグは無視して作成する
+	if err := conn(ctx, r.db).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "name"}}, DoNothing: true}).
+		Create(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	// 既存のタグはIDが返らないため、改めて取得する
+	tags = tags[:0]
+	if err := conn(ctx, r.db).Where("name IN ?", names).Order("name ASC").Find(&tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// ReplaceArticleTags 記事のタグを置き換える
+func (r *articleRepository) ReplaceArticleTags(ctx context.Context, article *model.Article, tags []model.Tag) error {
+	association := conn(ctx, r.db).Model(article).Omit("Tags.*").Association("Tags")
+	var err error
+	if len(tags) == 0 {
+		err = association.Clear()
+	} else {
+		err = association.Replace(tags)
+	}
+	if err != nil {
+		return err
+	}
+	article.Tags = tags
+	return nil
+}
+
+// ListArticleSlugs 作成者のスラッグのうち、baseそのものかbaseに連番を付けたものを取得する
+func (r *articleRepository) ListArticleSlugs(ctx context.Context, userID uint, base string) ([]model.ArticleSlug, error) {
+	var slugs []model.ArticleSlug
+	// スラッグは英小文字・数字・ハイフンのみのため、LIKEのエスケープは不要
+	if err := conn(ctx, r.db).
+		Where("user_id = ? AND (slug = ? OR slug LIKE ?)", userID, base, base+"-%").
+		Find(&slugs).Error; err != nil {
+		return nil, err
+	}
+	return slugs, nil
+}
+
+// ClaimDueScheduledArticles 公開予定日時を過ぎた記事を取得し、処理中として記録する
+// 複数のサーバーが同時に実行しても同じ記事を取得しないよう、行ロック(SKIP LOCKED)で排他する
+// 予約(publish_at)は公開と同じトランザクションで解除されるため、公開前にプロセスが停止しても
+// leaseを過ぎれば次の実行で再び取得される
+func (r *articleRepository) ClaimDueScheduledArticles(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]*model.Article, error) {
+	// DBに保存される精度にそろえ、公開時の条件(claimed_at)と一致させる
+	now = now.Truncate(time.Microsecond)
+
+	var articles []*model.Article
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate, Options: clause.LockingOptionsSkipLocked}).
+			Where("status = ? AND publish_at IS NOT NULL AND publish_at <= ?", "draft", now).
+			Where("claimed_at IS NULL OR claimed_at <= ?", now.Add(-lease)).
+			Order("publish_at ASC").
+			Limit(limit).
+			Find(&articles).Error; err != nil {
+			return err
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, 0, len(articles))
+		for _, article := range articles {
+			ids = append(ids, article.ID)
+			article.ClaimedAt = &now
+		}
+		return tx.Model(&model.Article{}).Where("id IN ?", ids).UpdateColumn("claimed_at", now).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return articles, nil
+}
+
+// PublishScheduledArticle ClaimDueScheduledArticlesで確保した記事を公開する
+// 確保した後に作成者がアーカイブしたり予約を変更したりした場合は更新せず、falseを返す
+func (r *articleRepository) PublishScheduledArticle(ctx context.Context, article *model.Article, now time.Time) (bool, error) {
+	if article.ClaimedAt == nil {
+		return false, nil
+	}
+	result := conn(ctx, r.db).
+		Model(&model.Article{}).
+		Where("id = ? AND status = ? AND publish_at IS NOT NULL AND publish_at <= ? AND claimed_at = ?", article.ID, "draft", now, *article.ClaimedAt).
+		Updates(map[string]any{
+			"status":     "published",
+			"publish_at": nil,
+			"claimed_at": nil,
+			"updated_at": now,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// ReleaseScheduledArticle 公開に失敗した記事の処理中の記録を消し、次の実行ですぐに再試行できるようにする
+func (r *articleRepository) ReleaseScheduledArticle(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&model.Article{}).Where("id = ?", id).UpdateColumn("claimed_at", nil).Error
 }
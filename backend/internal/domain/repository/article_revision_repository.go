@@ -0,0 +1,88 @@
+package repository
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/domain/model"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ArticleRevisionRepository interface {
+	GetRevision(ctx context.Context, articleID uint, version int) (*model.ArticleRevision, error)
+	ListRevisions(ctx context.Context, articleID uint, beforeVersion int, limit int) ([]*model.ArticleRevision, error)
+	CountRevisions(ctx context.Context, articleID uint) (int64, error)
+
+	CreateRevision(ctx context.Context, revision *model.ArticleRevision) error
+}
+
+type articleRevisionRepository struct {
+	db *gorm.DB
+}
+
+func NewArticleRevisionRepository(db *gorm.DB) ArticleRevisionRepository {
+	return &articleRevisionRepository{db: db}
+}
+
+func (r *articleRevisionRepository) GetRevision(ctx context.Context, articleID uint, version int) (*model.ArticleRevision, error) {
+	var revision model.ArticleRevision
+	if err := conn(ctx, r.db).Where("article_id = ? AND version = ?", articleID, version).First(&revision).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("revision not found")
+		}
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// ListRevisions 記事の履歴を新しい順に取得する(beforeVersionより前の履歴のみ)
+func (r *articleRevisionRepository) ListRevisions(ctx context.Context, articleID uint, beforeVersion int, limit int) ([]*model.ArticleRevision, error) {
+	var revisions []*model.ArticleRevision
+	query := conn(ctx, r.db).Where("article_id = ?", articleID)
+	if beforeVersion > 0 {
+		query = query.Where("version < ?", beforeVersion)
+	}
+	if err := query.Order("version DESC").Limit(limit).Find(&revisions).Error; err != nil {
+		return nil, err
+	}
+	return revisions, nil
+}
+
+// CountRevisions 記事の履歴数を取得する
+func (r *articleRevisionRepository) CountRevisions(ctx context.Context, articleID uint) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&model.ArticleRevision{}).Where("article_id = ?", articleID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CreateRevision 記事の次のバージョンとして履歴を保存する
+// 同じ記事の履歴が同時に作成されてもバージョンが重複しないよう、記事の行をロックして採番する
+func (r *articleRevisionRepository) CreateRevision(ctx context.Context, revision *model.ArticleRevision) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		var article model.Article
+		if err := tx.
+			Clauses(clause.Locking{Strength: clause.LockingStrengthUpdate}).
+			Select("id").
+			First(&article, revision.ArticleID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return apperror.NotFound("article not found")
+			}
+			return err
+		}
+
+		var latest int
+		if err := tx.Model(&model.ArticleRevision{}).
+			Where("article_id = ?", revision.ArticleID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+
+		revision.Version = latest + 1
+		return tx.Omit(clause.Associations).Create(revision).Error
+	})
+}
@@ -1,6 +1,7 @@
 package repository
 
 import (
+	"context"
 	"elasticsearch-sample/backend/internal/domain/model"
 )
 
@@ -12,11 +13,21 @@ type ArticleSearchRepository interface {
 	// エイリアスを新しいインデックスに切り替える
 	SwitchAlias(newIndexName string) error
 	// 記事ドキュメントを保存する(作成・更新)
-	Index(indexName *string, article *model.Article) error
+	Index(ctx context.Context, indexName *string, article *model.Article) error
 	// 記事ドキュメントを一括保存する
 	BulkIndex(indexName *string, articles []*model.Article) error
+	// 記事ドキュメントのいいね数を一括更新する
+	UpdateLikeCounts(ctx context.Context, counts []LikeCount) error
 	// 記事ドキュメントを削除する
-	Delete(id int64) error
-	// キーワードで記事を探す
-	SimpleSearch(keyword string) ([]*model.Article, error)
+	Delete(ctx context.Context, id int64) error
+	// キーワードとタグで公開記事を探す(いいねの多い記事ほど上位になる)
+	SimpleSearch(ctx context.Context, query ArticleSearchQuery) ([]*model.Article, error)
+	// 公開記事に多く付けられているタグを記事数の多い順に取得する
+	PopularTags(ctx context.Context, size int) ([]model.TagCount, error)
+}
+
+// ArticleSearchQuery 記事の検索条件
+type ArticleSearchQuery struct {
+	Keyword string   // 空の場合はキーワードで絞り込まない
+	Tags    []string // 指定したタグをすべて含む記事に絞り込む
 }
@@ -0,0 +1,68 @@
+package repository
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/domain/model"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AttachmentRepository interface {
+	GetAttachmentByID(ctx context.Context, id uint) (*model.Attachment, error)
+	ListAttachmentsByArticle(ctx context.Context, articleID uint) ([]*model.Attachment, error)
+	CountAttachmentsByArticle(ctx context.Context, articleID uint) (int64, error)
+
+	CreateAttachment(ctx context.Context, attachment *model.Attachment) (*model.Attachment, error)
+	DeleteAttachment(ctx context.Context, id uint) error
+}
+
+type attachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) AttachmentRepository {
+	return &attachmentRepository{db: db}
+}
+
+func (r *attachmentRepository) GetAttachmentByID(ctx context.Context, id uint) (*model.Attachment, error) {
+	var attachment model.Attachment
+	if err := conn(ctx, r.db).First(&attachment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("attachment not found")
+		}
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// ListAttachmentsByArticle 記事の添付ファイルを古い順に取得する
+func (r *attachmentRepository) ListAttachmentsByArticle(ctx context.Context, articleID uint) ([]*model.Attachment, error) {
+	var attachments []*model.Attachment
+	if err := conn(ctx, r.db).Where("article_id = ?", articleID).Order("id ASC").Find(&attachments).Error; err != nil {
+		return nil, err
+	}
+	return attachments, nil
+}
+
+// CountAttachmentsByArticle 記事の添付ファイル数を取得する
+func (r *attachmentRepository) CountAttachmentsByArticle(ctx context.Context, articleID uint) (int64, error) {
+	var count int64
+	if err := conn(ctx, r.db).Model(&model.Attachment{}).Where("article_id = ?", articleID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *attachmentRepository) CreateAttachment(ctx context.Context, attachment *model.Attachment) (*model.Attachment, error) {
+	if err := conn(ctx, r.db).Omit(clause.Associations).Create(attachment).Error; err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (r *attachmentRepository) DeleteAttachment(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&model.Attachment{}, id).Error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"io"
+)
+
+// BlobStore 添付ファイルなどのバイナリの保存先 (ローカルのファイルシステム、S3互換のストレージなど)
+type BlobStore interface {
+	// keyにファイルを保存する(同じkeyがある場合は上書きする)
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// keyのファイルを削除する(存在しない場合もエラーにしない)
+	Delete(ctx context.Context, key string) error
+	// keyのファイルを取得できるURLを返す
+	URL(ctx context.Context, key string) (string, error)
+	// 保存先に接続できるか確認する
+	Ping(ctx context.Context) error
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/domain/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BookmarkRepository interface {
+	// ブックマークしていなければ追加し、していれば取り消す(戻り値は変更後にブックマークしているか)
+	ToggleBookmark(ctx context.Context, articleID uint, userID uint) (bool, error)
+	ListBookmarks(ctx context.Context, userID uint, afterID uint, limit int) ([]*model.Bookmark, error)
+	CountBookmarks(ctx context.Context, userID uint) (int64, error)
+}
+
+type bookmarkRepository struct {
+	db *gorm.DB
+}
+
+func NewBookmarkRepository(db *gorm.DB) BookmarkRepository {
+	return &bookmarkRepository{db: db}
+}
+
+func (r *bookmarkRepository) ToggleBookmark(ctx context.Context, articleID uint, userID uint) (bool, error) {
+	result := conn(ctx, r.db).Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&model.Bookmark{})
+	if result.Error != nil {
+		return false, result.Error
+	}
+	if result.RowsAffected > 0 {
+		return false, nil
+	}
+
+	// 同時に追加された場合もブックマークしている状態になる
+	if err := conn(ctx, r.db).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Omit(clause.Associations).
+		Create(&model.Bookmark{ArticleID: articleID, UserID: userID}).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// ListBookmarks ユーザーのブックマークを新しい順に記事と合わせて取得する(afterIDより前のブックマークのみ)
+func (r *bookmarkRepository) ListBookmarks(ctx context.Context, userID uint, afterID uint, limit int) ([]*model.Bookmark, error) {
+	var bookmarks []*model.Bookmark
+	query := r.scopeVisibleBookmarks(ctx, userID)
+	if afterID > 0 {
+		query = query.Where("bookmarks.id < ?", afterID)
+	}
+	if err := query.Preload("Article.Tags").Order("bookmarks.id DESC").Limit(limit).Find(&bookmarks).Error; err != nil {
+		return nil, err
+	}
+	return bookmarks, nil
+}
+
+func (r *bookmarkRepository) CountBookmarks(ctx context.Context, userID uint) (int64, error) {
+	var count int64
+	if err := r.scopeVisibleBookmarks(ctx, userID).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// scopeVisibleBookmarks ブックマークした記事のうち、削除されておらず閲覧できるもの(公開中または自分の記事)に絞り込む
+func (r *bookmarkRepository) scopeVisibleBookmarks(ctx context.Context, userID uint) *gorm.DB {
+	return conn(ctx, r.db).
+		Model(&model.Bookmark{}).
+		Joins("JOIN articles ON articles.id = bookmarks.article_id AND articles.deleted_at IS NULL").
+		Where("bookmarks.user_id = ?", userID).
+		Where("articles.status = ? OR articles.user_id = ?", "published", userID)
+}
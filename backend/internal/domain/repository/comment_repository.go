@@ -0,0 +1,113 @@
+package repository
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/domain/model"
+	"errors"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CommentFilter コメント一覧の取得条件
+type CommentFilter struct {
+	ArticleID uint
+	ParentID  *uint    // nilの場合は返信ではないコメント
+	Statuses  []string // 空の場合は全ての状態
+	AuthorID  uint     // 0以外の場合、このユーザーのコメントは状態に関わらず含める
+}
+
+type CommentRepository interface {
+	GetCommentByID(ctx context.Context, id uint) (*model.Comment, error)
+	ListComments(ctx context.Context, filter CommentFilter, afterID uint, limit int) ([]*model.Comment, error)
+	CountComments(ctx context.Context, filter CommentFilter) (int64, error)
+
+	CreateComment(ctx context.Context, comment *model.Comment) (*model.Comment, error)
+	UpdateComment(ctx context.Context, comment *model.Comment) (*model.Comment, error)
+	DeleteComment(ctx context.Context, id uint) error
+}
+
+type commentRepository struct {
+	db *gorm.DB
+}
+
+func NewCommentRepository(db *gorm.DB) CommentRepository {
+	return &commentRepository{db: db}
+}
+
+func (r *commentRepository) GetCommentByID(ctx context.Context, id uint) (*model.Comment, error) {
+	var comment model.Comment
+	if err := conn(ctx, r.db).First(&comment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("comment not found")
+		}
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// ListComments コメントを古い順に取得する(afterIDより後のコメントのみ)
+func (r *commentRepository) ListComments(ctx context.Context, filter CommentFilter, afterID uint, limit int) ([]*model.Comment, error) {
+	var comments []*model.Comment
+	query := r.scopeComments(ctx, filter)
+	if afterID > 0 {
+		query = query.Where("id > ?", afterID)
+	}
+	if err := query.Order("id ASC").Limit(limit).Find(&comments).Error; err != nil {
+		return nil, err
+	}
+	return comments, nil
+}
+
+// CountComments 条件に一致するコメント数を取得する
+func (r *commentRepository) CountComments(ctx context.Context, filter CommentFilter) (int64, error) {
+	var count int64
+	if err := r.scopeComments(ctx, filter).Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *commentRepository) scopeComments(ctx context.Context, filter CommentFilter) *gorm.DB {
+	query := conn(ctx, r.db).Model(&model.Comment{}).Where("article_id = ?", filter.ArticleID)
+	if filter.ParentID != nil {
+		query = query.Where("parent_id = ?", *filter.ParentID)
+	} else {
+		query = query.Where("parent_id IS NULL")
+	}
+	if len(filter.Statuses) > 0 {
+		if filter.AuthorID != 0 {
+			query = query.Where("(status IN ? OR user_id = ?)", filter.Statuses, filter.AuthorID)
+		} else {
+			query = query.Where("status IN ?", filter.Statuses)
+		}
+	}
+	return query
+}
+
+func (r *commentRepository) CreateComment(ctx context.Context, comment *model.Comment) (*model.Comment, error) {
+	if err := conn(ctx, r.db).Omit(clause.Associations).Create(comment).Error; err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+func (r *commentRepository) UpdateComment(ctx context.Context, comment *model.Comment) (*model.Comment, error) {
+	if err := conn(ctx, r.db).Omit(clause.Associations).Save(comment).Error; err != nil {
+		return nil, err
+	}
+	return comment, nil
+}
+
+// DeleteComment コメントを返信も含めて削除する
+func (r *commentRepository) DeleteComment(ctx context.Context, id uint) error {
+	// 論理削除ではON DELETE CASCADEが働かないため、返信を再帰的にたどって削除する
+	return conn(ctx, r.db).Exec(`
+		WITH RECURSIVE thread AS (
+			SELECT id FROM comments WHERE id = ? AND deleted_at IS NULL
+			UNION ALL
+			SELECT c.id FROM comments c JOIN thread t ON c.parent_id = t.id WHERE c.deleted_at IS NULL
+		)
+		UPDATE comments SET deleted_at = NOW() WHERE id IN (SELECT id FROM thread)`, id).Error
+}
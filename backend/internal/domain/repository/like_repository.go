@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/domain/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LikeCount 記事のいいね数
+type LikeCount struct {
+	ArticleID uint
+	Count     int
+}
+
+type LikeRepository interface {
+	// いいねしていなければ追加し、していれば取り消す(戻り値は変更後にいいねしているか)
+	ToggleLike(ctx context.Context, articleID uint, userID uint) (bool, error)
+	// 指定した記事のうち、ユーザーがいいねしている記事のIDを返す
+	ListLikedArticleIDs(ctx context.Context, userID uint, articleIDs []uint) ([]uint, error)
+
+	// 検索エンジンに反映していないいいね数を取得する
+	ListUnindexedLikeCounts(ctx context.Context, limit int) ([]LikeCount, error)
+	MarkLikeCountsIndexed(ctx context.Context, counts []LikeCount) error
+}
+
+type likeRepository struct {
+	db *gorm.DB
+}
+
+func NewLikeRepository(db *gorm.DB) LikeRepository {
+	return &likeRepository{db: db}
+}
+
+// ToggleLike いいねの追加・取り消しと記事のいいね数の更新を同じトランザクションで行う
+func (r *likeRepository) ToggleLike(ctx context.Context, articleID uint, userID uint) (bool, error) {
+	liked := false
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("article_id = ? AND user_id = ?", articleID, userID).Delete(&model.ArticleLike{})
+		if result.Error != nil {
+			return result.Error
+		}
+		delta := -1
+		if result.RowsAffected == 0 {
+			// 同時に追加された場合は何もしない
+			result = tx.
+				Clauses(clause.OnConflict{DoNothing: true}).
+				Omit(clause.Associations).
+				Create(&model.ArticleLike{ArticleID: articleID, UserID: userID})
+			if result.Error != nil {
+				return result.Error
+			}
+			liked = true
+			delta = 1
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		return tx.Model(&model.Article{}).
+			Where("id = ?", articleID).
+			UpdateColumn("like_count", gorm.Expr("like_count + ?", delta)).Error
+	})
+	if err != nil {
+		return false, err
+	}
+	return liked, nil
+}
+
+func (r *likeRepository) ListLikedArticleIDs(ctx context.Context, userID uint, articleIDs []uint) ([]uint, error) {
+	var ids []uint
+	if err := conn(ctx, r.db).
+		Model(&model.ArticleLike{}).
+		Where("user_id = ? AND article_id IN ?", userID, articleIDs).
+		Pluck("article_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// ListUnindexedLikeCounts いいね数が検索エンジンに反映済みの値と異なる記事を取得する(削除済みの記事は除く)
+func (r *likeRepository) ListUnindexedLikeCounts(ctx context.Context, limit int) ([]LikeCount, error) {
+	var counts []LikeCount
+	if err := conn(ctx, r.db).
+		Model(&model.Article{}).
+		Select("id AS article_id, like_count AS count").
+		Where("like_count <> indexed_like_count").
+		Order("id ASC").
+		Limit(limit).
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// MarkLikeCountsIndexed 検索エンジンに反映したいいね数を記録する
+func (r *likeRepository) MarkLikeCountsIndexed(ctx context.Context, counts []LikeCount) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for _, count := range counts {
+			if err := tx.Model(&model.Article{}).
+				Where("id = ?", count.ArticleID).
+				UpdateColumn("indexed_like_count", count.Count).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
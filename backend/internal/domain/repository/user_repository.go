@@ -2,16 +2,21 @@ package repository
 
 import (
 	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
 	"elasticsearch-sample/backend/internal/domain/model"
+	"errors"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository interface {
 	GetUserByID(ctx context.Context, id uint) (*model.User, error)
 	GetUserByUID(ctx context.Context, uid string) (*model.User, error)
+	GetUsersByIDs(ctx context.Context, ids []uint) ([]*model.User, error)
 
 	CreateUser(ctx context.Context, user *model.User) error
+	UpsertUserByUID(ctx context.Context, user *model.User) error
 }
 
 type userRepository struct {
@@ -24,7 +29,10 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 
 func (r *userRepository) GetUserByID(ctx context.Context, id uint) (*model.User, error) {
 	var user model.User
-	if err := r.db.First(&user, id).Error; err != nil {
+	if err := conn(ctx, r.db).First(&user, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("user not found")
+		}
 		return nil, err
 	}
 	return &user, nil
@@ -32,15 +40,50 @@ func (r *userRepository) GetUserByID(ctx context.Context, id uint) (*model.User,
 
 func (r *userRepository) GetUserByUID(ctx context.Context, uid string) (*model.User, error) {
 	var user model.User
-	if err := r.db.Where("uid = ?", uid).First(&user).Error; err != nil {
+	if err := conn(ctx, r.db).Where("uid = ?", uid).First(&user).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperror.NotFound("user not found")
+		}
 		return nil, err
 	}
 	return &user, nil
 }
 
+func (r *userRepository) GetUsersByIDs(ctx context.Context, ids []uint) ([]*model.User, error) {
+	var users []*model.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := conn(ctx, r.db).Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *userRepository) CreateUser(ctx context.Context, user *model.User) error {
-	if err := r.db.Create(user).Error; err != nil {
+	if err := conn(ctx, r.db).Create(user).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return apperror.Conflict("user already exists")
+		}
 		return err
 	}
 	return nil
 }
+
+// UpsertUserByUID UIDが一致するユーザーがいれば更新、いなければ作成する
+// 同時に複数のリクエストが来ても、UIDの一意制約によって1件だけ作成される
+func (r *userRepository) UpsertUserByUID(ctx context.Context, user *model.User) error {
+	// 空の値で既存のプロフィールを上書きしない
+	columns := []string{"updated_at"}
+	if user.DisplayName != "" {
+		columns = append(columns, "display_name")
+	}
+	if user.Email != "" {
+		columns = append(columns, "email")
+	}
+
+	return conn(ctx, r.db).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "uid"}},
+		DoUpdates: clause.AssignmentColumns(columns),
+	}).Create(user).Error
+}
@@ -0,0 +1,132 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"elasticsearch-sample/backend/internal/logging"
+)
+
+var logger = logging.Component("health")
+
+// 1つの依存先の確認にかける時間の上限
+const checkTimeout = 3 * time.Second
+
+const (
+	StatusOK       = "ok"
+	StatusError    = "error"
+	StatusDraining = "draining" // 停止処理中のため新しいリクエストを受け付けない
+)
+
+// Check 依存先(DBや検索エンジンなど)の疎通確認
+type Check struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// CheckResult 依存先ごとの確認結果
+type CheckResult struct {
+	Status    string  `json:"status"`
+	LatencyMS float64 `json:"latencyMs"`
+	Error     string  `json:"error,omitempty"`
+}
+
+// Report /readyz のレスポンス
+type Report struct {
+	Status string                 `json:"status"`
+	Checks map[string]CheckResult `json:"checks"`
+}
+
+// Checker プロセスの生存確認(/healthz)と、依存先を含めた準備完了の確認(/readyz)を提供する
+type Checker struct {
+	checks   []Check
+	draining atomic.Bool
+}
+
+func NewChecker(checks ...Check) *Checker {
+	return &Checker{checks: checks}
+}
+
+// Drain 停止処理の開始を記録し、以降の/readyzを失敗させる (ロードバランサーから外すため)
+func (c *Checker) Drain() {
+	c.draining.Store(true)
+}
+
+// Ready 全ての依存先を並行して確認する
+func (c *Checker) Ready(ctx context.Context) Report {
+	report := Report{Status: StatusOK, Checks: make(map[string]CheckResult, len(c.checks))}
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+	for _, check := range c.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result := run(ctx, check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Checks[check.Name] = result
+			if result.Status != StatusOK {
+				report.Status = StatusError
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c.draining.Load() {
+		report.Status = StatusDraining
+	}
+	return report
+}
+
+// run タイムアウト付きで確認を実行し、所要時間を計測する
+func run(ctx context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+	result := CheckResult{
+		Status:    StatusOK,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		logger.WarnContext(ctx, "⚠️ ヘルスチェックに失敗しました", "check", check.Name, "error", err)
+		result.Status = StatusError
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// LivenessHandler プロセスが動いていれば常に200を返す
+func (c *Checker) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": StatusOK})
+	})
+}
+
+// ReadinessHandler 全ての依存先が正常なら200、それ以外は503を返す
+func (c *Checker) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		report := c.Ready(r.Context())
+		status := http.StatusOK
+		if report.Status != StatusOK {
+			status = http.StatusServiceUnavailable
+		}
+		writeJSON(w, status, report)
+	})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
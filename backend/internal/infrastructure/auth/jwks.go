@@ -0,0 +1,223 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	jwksCacheTTL         = time.Hour        // 公開鍵のキャッシュ期間
+	jwksMinRefreshPeriod = time.Minute      // 未知のkidによる再取得の最短間隔
+	jwksFetchTimeout     = 10 * time.Second // JWKS取得のタイムアウト
+)
+
+// jwksKeySet JWKS(URLまたはファイル)から取得した公開鍵をキャッシュする
+type jwksKeySet struct {
+	url    string
+	file   string
+	client *http.Client
+
+	// 取得は同時に1回だけ行い、取得中もキャッシュ済みの鍵は読み取れるようにする
+	group     singleflight.Group
+	mu        sync.RWMutex
+	keys      map[string]any
+	fetchedAt time.Time
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func newJWKSKeySet(url string, file string) *jwksKeySet {
+	return &jwksKeySet{
+		url:    url,
+		file:   file,
+		client: &http.Client{Timeout: jwksFetchTimeout},
+		keys:   map[string]any{},
+	}
+}
+
+// key: kidに対応する公開鍵を返す(キャッシュが古い・kidが未知の場合は再取得)
+func (s *jwksKeySet) key(ctx context.Context, kid string) (any, error) {
+	key, ok, fetchedAt := s.cached(kid)
+	expired := time.Since(fetchedAt) > jwksCacheTTL
+	if ok && !expired {
+		return key, nil
+	}
+
+	// 未知のkidで何度も取得しに行かないよう、再取得の間隔を制限する
+	if expired || time.Since(fetchedAt) > jwksMinRefreshPeriod {
+		if err := s.refresh(ctx, fetchedAt); err != nil {
+			if ok {
+				logger.WarnContext(ctx, "⚠️ JWKSの再取得に失敗したため、キャッシュ済みの鍵を使用します", "error", err)
+				return key, nil
+			}
+			return nil, err
+		}
+	}
+
+	key, ok, _ = s.cached(kid)
+	if !ok {
+		return nil, fmt.Errorf("unknown key id: %q", kid)
+	}
+	return key, nil
+}
+
+// cached: キャッシュ済みの鍵と取得日時を返す
+func (s *jwksKeySet) cached(kid string) (any, bool, time.Time) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key, ok := s.keys[kid]
+	return key, ok, s.fetchedAt
+}
+
+// refresh: JWKSを取得してキャッシュを更新する
+// 同時に呼ばれた場合は取得を1回にまとめ、ロックは取得後の差し替えのときだけ取得する
+func (s *jwksKeySet) refresh(ctx context.Context, fetchedAt time.Time) error {
+	ch := s.group.DoChan("jwks", func() (any, error) {
+		// 待っている間に他のリクエストが更新した場合は取得し直さない
+		s.mu.RLock()
+		updated := s.fetchedAt.After(fetchedAt)
+		s.mu.RUnlock()
+		if updated {
+			return nil, nil
+		}
+
+		// 呼び出し元のリクエストが先に終わっても、待っている他のリクエストのために取得を続ける
+		keys, err := s.load(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+
+		s.mu.Lock()
+		s.keys = keys
+		s.fetchedAt = time.Now()
+		s.mu.Unlock()
+		return nil, nil
+	})
+
+	select {
+	case res := <-ch:
+		return res.Err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// load: JWKSを取得して公開鍵に変換する
+func (s *jwksKeySet) load(ctx context.Context) (map[string]any, error) {
+	data, err := s.fetch(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch jwks: %w", err)
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &jwks); err != nil {
+		return nil, fmt.Errorf("failed to parse jwks: %w", err)
+	}
+
+	keys := map[string]any{}
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			logger.WarnContext(ctx, "⚠️ JWKの読み込みをスキップしました", "kid", jwk.Kid, "error", err)
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	return keys, nil
+}
+
+func (s *jwksKeySet) fetch(ctx context.Context) ([]byte, error) {
+	if s.file != "" {
+		return os.ReadFile(s.file)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code: %d", res.StatusCode)
+	}
+	return io.ReadAll(res.Body)
+}
+
+// publicKey: JWKを公開鍵に変換する
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,108 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"elasticsearch-sample/backend/internal/config"
+	"elasticsearch-sample/backend/internal/logging"
+)
+
+var logger = logging.Component("auth")
+
+// 有効期限などの時刻検証で許容するずれ
+const clockSkewLeeway = 30 * time.Second
+
+// ErrInvalidToken トークンの署名・有効期限・発行者などの検証に失敗した場合のエラー
+var ErrInvalidToken = errors.New("invalid token")
+
+// Claims 検証済みトークンのクレーム
+type Claims struct {
+	jwt.RegisteredClaims
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// TokenVerifier 署名付きトークン(JWT)を検証する
+type TokenVerifier struct {
+	hmacSecret []byte
+	keySet     *jwksKeySet
+	methods    []string
+	issuer     string
+	audience   string
+}
+
+// NewTokenVerifier は設定に従ってトークン検証器を初期化します
+//   - HS256Secret: HS256の共有シークレット(ローカル開発用)
+//   - JWKSURL / JWKSFile: RS256/ES256の公開鍵(JWKS)の取得先
+//   - Issuer / Audience: 指定した場合はiss/audを検証
+func NewTokenVerifier(cfg config.AuthConfig) (*TokenVerifier, error) {
+	v := &TokenVerifier{
+		issuer:   cfg.Issuer,
+		audience: cfg.Audience,
+	}
+
+	if cfg.HS256Secret != "" {
+		v.hmacSecret = []byte(cfg.HS256Secret.Value())
+		v.methods = append(v.methods, jwt.SigningMethodHS256.Alg())
+	}
+
+	if cfg.JWKSURL != "" || cfg.JWKSFile != "" {
+		v.keySet = newJWKSKeySet(cfg.JWKSURL, cfg.JWKSFile)
+		v.methods = append(v.methods, jwt.SigningMethodRS256.Alg(), jwt.SigningMethodES256.Alg())
+	}
+
+	if len(v.methods) == 0 {
+		return nil, fmt.Errorf("❌ 認証設定がありません (AUTH_HS256_SECRET または AUTH_JWKS_URL/AUTH_JWKS_FILE を設定してください)")
+	}
+
+	logger.Info("✅ トークン検証を初期化しました", "algorithms", v.methods)
+
+	return v, nil
+}
+
+// Verify: トークンを検証し、クレームを返す
+func (v *TokenVerifier) Verify(ctx context.Context, tokenString string) (*Claims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods(v.methods),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(clockSkewLeeway),
+	}
+	if v.issuer != "" {
+		options = append(options, jwt.WithIssuer(v.issuer))
+	}
+	if v.audience != "" {
+		options = append(options, jwt.WithAudience(v.audience))
+	}
+
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
+		return v.key(ctx, token)
+	}, options...)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidToken, err)
+	}
+	if claims.Subject == "" {
+		return nil, fmt.Errorf("%w: subject is empty", ErrInvalidToken)
+	}
+
+	return claims, nil
+}
+
+// key: トークンの署名アルゴリズムに応じた検証鍵を返す
+func (v *TokenVerifier) key(ctx context.Context, token *jwt.Token) (any, error) {
+	switch token.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		return v.hmacSecret, nil
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+		kid, _ := token.Header["kid"].(string)
+		return v.keySet.key(ctx, kid)
+	default:
+		return nil, fmt.Errorf("unexpected signing method: %s", token.Method.Alg())
+	}
+}
@@ -0,0 +1,72 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"elasticsearch-sample/backend/internal/logging"
+)
+
+var logger = logging.Component("db")
+
+// queryLogger GORMのログをslogに出力する
+// 失敗したSQLはERROR、時間のかかったSQLはWARN、それ以外はDEBUGで出力する
+// 出力するかどうかはコンポーネント(db)のログレベルで決まる
+type queryLogger struct {
+	slowThreshold time.Duration // 0の場合は警告しない
+}
+
+var _ gormlogger.Interface = queryLogger{}
+
+// LogMode GORMのログレベルは使わない
+func (l queryLogger) LogMode(gormlogger.LogLevel) gormlogger.Interface {
+	return l
+}
+
+func (queryLogger) Info(ctx context.Context, msg string, args ...any) {
+	logger.InfoContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (queryLogger) Warn(ctx context.Context, msg string, args ...any) {
+	logger.WarnContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (queryLogger) Error(ctx context.Context, msg string, args ...any) {
+	logger.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+}
+
+func (l queryLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	elapsed := time.Since(begin)
+
+	level := slog.LevelDebug
+	msg := "SQL"
+	switch {
+	// 見つからないことは呼び出し元で扱うため、エラーとして出力しない
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
+		level = slog.LevelError
+		msg = "❌ SQLの実行に失敗しました"
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		level = slog.LevelWarn
+		msg = "⚠️ SQLの実行に時間がかかりました"
+	}
+	if !logger.Enabled(ctx, level) {
+		return
+	}
+
+	sql, rows := fc()
+	args := []any{
+		"sql", sql,
+		"rows", rows,
+		"duration_ms", elapsed.Milliseconds(),
+	}
+	if level == slog.LevelError {
+		args = append(args, "error", err)
+	}
+	logger.Log(ctx, level, msg, args...)
+}
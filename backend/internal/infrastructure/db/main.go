@@ -1,48 +1,56 @@
 package db
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
-	"os"
+
+	"elasticsearch-sample/backend/internal/config"
 
 	"github.com/go-gormigrate/gormigrate/v2"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
-	"gorm.io/gorm/logger"
 )
 
-var DB *gorm.DB
+// Connect 設定に従ってデータベースに接続する
+func Connect(cfg config.DatabaseConfig) (*gorm.DB, error) {
+	db, err := gorm.Open(postgres.Open(cfg.DSN()), &gorm.Config{
+		Logger: queryLogger{slowThreshold: cfg.SlowQueryThreshold},
+		// 一意制約違反などをgorm.ErrDuplicatedKeyに変換する
+		TranslateError: true,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("❌ データベース接続に失敗しました: %w", err)
+	}
+	if err := errors.Join(db.Use(metricsPlugin{}), db.Use(tracingPlugin{})); err != nil {
+		return nil, fmt.Errorf("❌ メトリクス・トレースの初期化に失敗しました: %w", err)
+	}
 
-func ConnectDB() {
-	var dsn string
+	logger.Info("✅ データベース接続が成功しました")
+	return db, nil
+}
 
-	if dbURL := os.Getenv("DATABASE_URL"); dbURL != "" {
-		dsn = dbURL
-	} else {
-		// 個別の環境変数を使用
-		host := os.Getenv("DB_HOST")
-		user := os.Getenv("DB_USER")
-		password := os.Getenv("DB_PASSWORD")
-		dbname := os.Getenv("DB_NAME")
-		port := os.Getenv("DB_PORT")
-		dsn = fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=disable",
-			host, user, password, dbname, port)
+// Close データベースとの接続を閉じる
+func Close(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
 	}
+	return sqlDB.Close()
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
-	})
+// Ping コネクションプールを通してデータベースへの疎通を確認する
+func Ping(ctx context.Context, db *gorm.DB) error {
+	sqlDB, err := db.DB()
 	if err != nil {
-		panic("❌ データベース接続に失敗しました: " + err.Error())
+		return err
 	}
-
-	DB = db
-	log.Printf("✅ データベース接続が成功しました")
+	return sqlDB.PingContext(ctx)
 }
 
 // RollbackTo 指定したマイグレーションIDまでロールバックする
-func RollbackTo(migrationID string) error {
-	if DB == nil {
+func RollbackTo(db *gorm.DB, migrationID string) error {
+	if db == nil {
 		return fmt.Errorf("データベースが接続されていません")
 	}
 
@@ -59,22 +67,22 @@ func RollbackTo(migrationID string) error {
 		return fmt.Errorf("マイグレーションID '%s' が見つかりません", migrationID)
 	}
 
-	m := gormigrate.New(DB, gormigrate.DefaultOptions, migrations)
+	m := gormigrate.New(db, gormigrate.DefaultOptions, migrations)
 
-	log.Printf("🔄 マイグレーション '%s' までロールバックを開始します...", migrationID)
+	logger.Info("🔄 ロールバックを開始します...", "migration", migrationID)
 
 	if err := m.RollbackTo(migrationID); err != nil {
-		log.Printf("❌ ロールバックに失敗しました: %v", err)
+		logger.Error("❌ ロールバックに失敗しました", "error", err)
 		return fmt.Errorf("ロールバックに失敗しました: %w", err)
 	}
 
-	log.Printf("✅ マイグレーション '%s' までロールバックしました", migrationID)
+	logger.Info("✅ ロールバックしました", "migration", migrationID)
 	return nil
 }
 
 // RollbackLast 最後のマイグレーションをロールバックする
-func RollbackLast() error {
-	if DB == nil {
+func RollbackLast(db *gorm.DB) error {
+	if db == nil {
 		return fmt.Errorf("データベースが接続されていません")
 	}
 
@@ -83,10 +91,10 @@ func RollbackLast() error {
 		return fmt.Errorf("マイグレーションが定義されていません")
 	}
 
-	m := gormigrate.New(DB, gormigrate.DefaultOptions, migrations)
+	m := gormigrate.New(db, gormigrate.DefaultOptions, migrations)
 
 	// 最後に実行されたマイグレーションを確認
-	lastMigration, err := getLastRunMigration()
+	lastMigration, err := getLastRunMigration(db)
 	if err != nil {
 		return fmt.Errorf("最後のマイグレーションの確認に失敗しました: %w", err)
 	}
@@ -94,20 +102,20 @@ func RollbackLast() error {
 		return fmt.Errorf("実行されたマイグレーションがありません")
 	}
 
-	log.Printf("🔄 最後のマイグレーション '%s' をロールバックします...", lastMigration)
+	logger.Info("🔄 最後のマイグレーションをロールバックします...", "migration", lastMigration)
 
 	if err := m.RollbackLast(); err != nil {
-		log.Printf("❌ 最後のマイグレーションのロールバックに失敗しました: %v", err)
+		logger.Error("❌ 最後のマイグレーションのロールバックに失敗しました", "error", err)
 		return fmt.Errorf("最後のマイグレーションのロールバックに失敗しました: %w", err)
 	}
 
-	log.Printf("✅ 最後のマイグレーション '%s' をロールバックしました", lastMigration)
+	logger.Info("✅ 最後のマイグレーションをロールバックしました", "migration", lastMigration)
 	return nil
 }
 
 // getLastRunMigration 最後に実行されたマイグレーションIDを取得する
-func getLastRunMigration() (string, error) {
-	if DB == nil {
+func getLastRunMigration(db *gorm.DB) (string, error) {
+	if db == nil {
 		return "", fmt.Errorf("データベースが接続されていません")
 	}
 
@@ -115,7 +123,7 @@ func getLastRunMigration() (string, error) {
 		ID string `gorm:"column:id"`
 	}
 
-	err := DB.Table("migrations").Order("id DESC").First(&migration).Error
+	err := db.Table("migrations").Order("id DESC").First(&migration).Error
 	if err != nil {
 		if err == gorm.ErrRecordNotFound {
 			return "", nil // マイグレーションが実行されていない
@@ -127,8 +135,8 @@ func getLastRunMigration() (string, error) {
 }
 
 // GetMigrationStatus マイグレーションの状態を取得する
-func GetMigrationStatus() ([]string, error) {
-	if DB == nil {
+func GetMigrationStatus(db *gorm.DB) ([]string, error) {
+	if db == nil {
 		return nil, fmt.Errorf("データベースが接続されていません")
 	}
 
@@ -136,7 +144,7 @@ func GetMigrationStatus() ([]string, error) {
 		ID string `gorm:"column:id"`
 	}
 
-	err := DB.Table("migrations").Order("id ASC").Find(&migrations).Error
+	err := db.Table("migrations").Order("id ASC").Find(&migrations).Error
 	if err != nil {
 		return nil, fmt.Errorf("マイグレーション状態の取得に失敗しました: %w", err)
 	}
@@ -150,8 +158,8 @@ func GetMigrationStatus() ([]string, error) {
 }
 
 // MigrateTo 指定したマイグレーションIDまでマイグレーションを実行する
-func MigrateTo(migrationID string) error {
-	if DB == nil {
+func MigrateTo(db *gorm.DB, migrationID string) error {
+	if db == nil {
 		return fmt.Errorf("データベースが接続されていません")
 	}
 
@@ -168,22 +176,22 @@ func MigrateTo(migrationID string) error {
 		return fmt.Errorf("マイグレーションID '%s' が見つかりません", migrationID)
 	}
 
-	m := gormigrate.New(DB, gormigrate.DefaultOptions, migrations)
+	m := gormigrate.New(db, gormigrate.DefaultOptions, migrations)
 
-	log.Printf("🔄 マイグレーション '%s' まで実行を開始します...", migrationID)
+	logger.Info("🔄 マイグレーションを開始します...", "migration", migrationID)
 
 	if err := m.MigrateTo(migrationID); err != nil {
-		log.Printf("❌ マイグレーションに失敗しました: %v", err)
+		logger.Error("❌ マイグレーションに失敗しました", "error", err)
 		return fmt.Errorf("マイグレーションに失敗しました: %w", err)
 	}
 
-	log.Printf("✅ マイグレーション '%s' まで実行しました", migrationID)
+	logger.Info("✅ マイグレーションを実行しました", "migration", migrationID)
 	return nil
 }
 
 // RunMigrations マイグレーションを実行する（サーバー起動時には呼ばれない）
-func RunMigrations() error {
-	if DB == nil {
+func RunMigrations(db *gorm.DB) error {
+	if db == nil {
 		return fmt.Errorf("データベースが接続されていません")
 	}
 
@@ -192,16 +200,15 @@ func RunMigrations() error {
 		return fmt.Errorf("マイグレーションが定義されていません")
 	}
 
-	m := gormigrate.New(DB, gormigrate.DefaultOptions, migrations)
+	m := gormigrate.New(db, gormigrate.DefaultOptions, migrations)
 
-	log.Printf("🔄 マイグレーションを開始します...")
-	log.Printf("📋 実行予定のマイグレーション数: %d", len(migrations))
+	logger.Info("🔄 マイグレーションを開始します...", "count", len(migrations))
 
 	if err := m.Migrate(); err != nil {
-		log.Printf("❌ マイグレーションに失敗しました: %v", err)
+		logger.Error("❌ マイグレーションに失敗しました", "error", err)
 		return fmt.Errorf("マイグレーションに失敗しました: %w", err)
 	}
 
-	log.Printf("✅ マイグレーションが正常に実行されました")
+	logger.Info("✅ マイグレーションが正常に実行されました")
 	return nil
 }
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"gorm.io/gorm"
+
+	"elasticsearch-sample/backend/internal/metrics"
+)
+
+const metricsStartKey = "metrics:start_time"
+
+var queryDuration = metrics.Factory.NewHistogramVec(prometheus.HistogramOpts{
+	Name:    "db_query_duration_seconds",
+	Help:    "GORMで実行したクエリの所要時間",
+	Buckets: metrics.LatencyBuckets,
+}, []string{"operation", "table", "status"})
+
+// metricsPlugin クエリの所要時間を計測するGORMプラグイン
+type metricsPlugin struct{}
+
+func (metricsPlugin) Name() string {
+	return "metrics"
+}
+
+// Initialize 各操作のコールバックの前後に計測処理を登録する
+func (metricsPlugin) Initialize(db *gorm.DB) error {
+	return registerAround(db, "metrics", func(string) func(*gorm.DB) { return startTimer }, observeDuration)
+}
+
+func startTimer(tx *gorm.DB) {
+	tx.InstanceSet(metricsStartKey, time.Now())
+}
+
+func observeDuration(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet(metricsStartKey)
+		if !ok {
+			return
+		}
+		start, ok := v.(time.Time)
+		if !ok {
+			return
+		}
+
+		status := "ok"
+		if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			status = "error"
+		}
+		queryDuration.WithLabelValues(operation, tx.Statement.Table, status).Observe(time.Since(start).Seconds())
+	}
+}
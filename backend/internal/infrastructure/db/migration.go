@@ -28,5 +28,203 @@ func getMigrations() []*gormigrate.Migration {
 				return tx.Migrator().DropTable(&model.Article{})
 			},
 		},
+		{
+			ID: "202610181000_add_publish_at_to_articles",
+			Migrate: func(tx *gorm.DB) error {
+				if !tx.Migrator().HasColumn(&model.Article{}, "PublishAt") {
+					if err := tx.Migrator().AddColumn(&model.Article{}, "PublishAt"); err != nil {
+						return err
+					}
+				}
+				if !tx.Migrator().HasIndex(&model.Article{}, "idx_publish_at_on_articles") {
+					return tx.Migrator().CreateIndex(&model.Article{}, "idx_publish_at_on_articles")
+				}
+				return nil
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropIndex(&model.Article{}, "idx_publish_at_on_articles"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropColumn(&model.Article{}, "PublishAt")
+			},
+		},
+		{
+			ID: "202610181010_add_profile_to_users",
+			Migrate: func(tx *gorm.DB) error {
+				return addColumns(tx, &model.User{}, "DisplayName", "Email")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return dropColumns(tx, &model.User{}, "DisplayName", "Email")
+			},
+		},
+		{
+			ID: "202610181020_add_is_admin_to_users",
+			Migrate: func(tx *gorm.DB) error {
+				return addColumns(tx, &model.User{}, "IsAdmin")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return dropColumns(tx, &model.User{}, "IsAdmin")
+			},
+		},
+		{
+			ID: "202610181030_create_api_keys",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.APIKey{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.APIKey{})
+			},
+		},
+		{
+			ID: "202610191000_create_tags",
+			Migrate: func(tx *gorm.DB) error {
+				// 中間テーブル(article_tags)は記事のモデルの関連から作成される
+				return tx.AutoMigrate(&model.Tag{}, &model.Article{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable("article_tags"); err != nil {
+					return err
+				}
+				return tx.Migrator().DropTable(&model.Tag{})
+			},
+		},
+		{
+			ID: "202610191010_create_article_revisions",
+			Migrate: func(tx *gorm.DB) error {
+				if err := tx.AutoMigrate(&model.ArticleRevision{}); err != nil {
+					return err
+				}
+				// 既存の記事は現在の内容を最初の履歴とする
+				return tx.Exec(`
+					INSERT INTO article_revisions (article_id, version, title, content, status, editor_id, created_at)
+					SELECT id, 1, title, content, status, user_id, updated_at FROM articles
+					WHERE deleted_at IS NULL
+					ON CONFLICT DO NOTHING`).Error
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.ArticleRevision{})
+			},
+		},
+		{
+			ID: "202610191020_create_comments",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Comment{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.Comment{})
+			},
+		},
+		{
+			ID: "202610191030_add_slug_to_articles",
+			Migrate: func(tx *gorm.DB) error {
+				if err := addColumns(tx, &model.Article{}, "Slug"); err != nil {
+					return err
+				}
+				if err := tx.AutoMigrate(&model.ArticleSlug{}); err != nil {
+					return err
+				}
+				return backfillArticleSlugs(tx)
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&model.ArticleSlug{}); err != nil {
+					return err
+				}
+				return dropColumns(tx, &model.Article{}, "Slug")
+			},
+		},
+		{
+			ID: "202610191040_create_attachments",
+			Migrate: func(tx *gorm.DB) error {
+				return tx.AutoMigrate(&model.Attachment{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return tx.Migrator().DropTable(&model.Attachment{})
+			},
+		},
+		{
+			ID: "202610191050_create_likes_and_bookmarks",
+			Migrate: func(tx *gorm.DB) error {
+				if err := addColumns(tx, &model.Article{}, "LikeCount", "IndexedLikeCount"); err != nil {
+					return err
+				}
+				return tx.AutoMigrate(&model.ArticleLike{}, &model.Bookmark{})
+			},
+			Rollback: func(tx *gorm.DB) error {
+				if err := tx.Migrator().DropTable(&model.Bookmark{}, &model.ArticleLike{}); err != nil {
+					return err
+				}
+				return dropColumns(tx, &model.Article{}, "LikeCount", "IndexedLikeCount")
+			},
+		},
+		{
+			ID: "202610191100_add_claimed_at_to_articles",
+			Migrate: func(tx *gorm.DB) error {
+				return addColumns(tx, &model.Article{}, "ClaimedAt")
+			},
+			Rollback: func(tx *gorm.DB) error {
+				return dropColumns(tx, &model.Article{}, "ClaimedAt")
+			},
+		},
+	}
+}
+
+// addColumns 存在しないカラムを追加する
+// 作成時のマイグレーションは現在のモデルでテーブルを作るため、既にカラムがある場合がある
+func addColumns(tx *gorm.DB, value any, columns ...string) error {
+	for _, column := range columns {
+		if tx.Migrator().HasColumn(value, column) {
+			continue
+		}
+		if err := tx.Migrator().AddColumn(value, column); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// backfillArticleSlugs スラッグが未設定の記事(削除済みを含む)にタイトルからスラッグを設定する
+func backfillArticleSlugs(tx *gorm.DB) error {
+	// 作成者ごとの使用済みスラッグ
+	taken := map[uint]map[string]bool{}
+	var existing []model.ArticleSlug
+	if err := tx.Find(&existing).Error; err != nil {
+		return err
+	}
+	for _, s := range existing {
+		if taken[s.UserID] == nil {
+			taken[s.UserID] = map[string]bool{}
+		}
+		taken[s.UserID][s.Slug] = true
+	}
+
+	// 一括取得中の条件を引き継がないよう、更新は新しいセッションで行う
+	db := tx.Session(&gorm.Session{NewDB: true})
+	var articles []*model.Article
+	return tx.Unscoped().Where("slug = ''").Order("id ASC").FindInBatches(&articles, 100, func(*gorm.DB, int) error {
+		for _, article := range articles {
+			if taken[article.UserID] == nil {
+				taken[article.UserID] = map[string]bool{}
+			}
+			slug := model.UniqueSlug(model.SlugFromTitle(article.Title), func(slug string) bool { return taken[article.UserID][slug] })
+			taken[article.UserID][slug] = true
+
+			if err := db.Model(&model.Article{}).Unscoped().Where("id = ?", article.ID).UpdateColumn("slug", slug).Error; err != nil {
+				return err
+			}
+			if err := db.Create(&model.ArticleSlug{ArticleID: article.ID, UserID: article.UserID, Slug: slug}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// dropColumns カラムを削除する
+func dropColumns(tx *gorm.DB, value any, columns ...string) error {
+	for _, column := range columns {
+		if err := tx.Migrator().DropColumn(value, column); err != nil {
+			return err
+		}
 	}
+	return nil
 }
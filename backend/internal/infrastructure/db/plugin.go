@@ -0,0 +1,35 @@
+package db
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// registerAround GORMの各操作(create/query/update/delete/row/raw)の実行前後にコールバックを登録する
+// before/afterは操作名を受け取り、その操作用のコールバックを返す
+func registerAround(db *gorm.DB, plugin string, before, after func(operation string) func(*gorm.DB)) error {
+	type register func(name string, fn func(*gorm.DB)) error
+
+	cb := db.Callback()
+	hooks := []struct {
+		operation     string
+		before, after register
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	var errs []error
+	for _, h := range hooks {
+		errs = append(errs,
+			h.before(plugin+":before_"+h.operation, before(h.operation)),
+			h.after(plugin+":after_"+h.operation, after(h.operation)),
+		)
+	}
+	return errors.Join(errs...)
+}
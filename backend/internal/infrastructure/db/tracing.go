@@ -0,0 +1,65 @@
+package db
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const tracingSpanKey = "tracing:span"
+
+var tracer = otel.Tracer("elasticsearch-sample/backend/internal/infrastructure/db")
+
+// tracingPlugin SQLの実行ごとにスパンを記録するGORMプラグイン
+// 呼び出し元のContext(WithContext)にスパンがあれば、その子スパンになる
+type tracingPlugin struct{}
+
+func (tracingPlugin) Name() string {
+	return "tracing"
+}
+
+func (tracingPlugin) Initialize(db *gorm.DB) error {
+	return registerAround(db, "tracing", startSpan, endSpan)
+}
+
+func startSpan(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		ctx, span := tracer.Start(tx.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(semconv.DBSystemPostgreSQL),
+		)
+		tx.Statement.Context = ctx
+		tx.InstanceSet(tracingSpanKey, span)
+	}
+}
+
+func endSpan(operation string) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		v, ok := tx.InstanceGet(tracingSpanKey)
+		if !ok {
+			return
+		}
+		span, ok := v.(trace.Span)
+		if !ok {
+			return
+		}
+		defer span.End()
+
+		// SQLはプレースホルダーのまま記録する(パラメータの値は含めない)
+		span.SetAttributes(
+			semconv.DBQueryText(tx.Statement.SQL.String()),
+			semconv.DBCollectionName(tx.Statement.Table),
+			semconv.DBOperationName(operation),
+			attribute.Int64("db.rows_affected", tx.Statement.RowsAffected),
+		)
+		if tx.Error != nil && !errors.Is(tx.Error, gorm.ErrRecordNotFound) {
+			span.RecordError(tx.Error)
+			span.SetStatus(codes.Error, tx.Error.Error())
+		}
+	}
+}
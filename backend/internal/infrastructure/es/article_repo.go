@@ -2,17 +2,24 @@ package es
 
 import (
 	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
 	"elasticsearch-sample/backend/internal/domain/model"
 	"elasticsearch-sample/backend/internal/domain/repository"
+	"elasticsearch-sample/backend/internal/markdown"
 	"encoding/json"
 	"fmt"
-	"log"
+	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/elastic/go-elasticsearch/v9/typedapi/core/search"
 	"github.com/elastic/go-elasticsearch/v9/typedapi/indices/create"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/some"
 	"github.com/elastic/go-elasticsearch/v9/typedapi/types"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types/enums/fieldvaluefactormodifier"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types/enums/functionboostmode"
 )
 
 const (
@@ -21,20 +28,44 @@ const (
 
 type articleSearchRepo struct {
 	client *Client
+
+	// 再構築中のインデックスの作成時刻(エイリアス切り替えまでの所要時間の計測用)
+	mu             sync.Mutex
+	indexCreatedAt map[string]time.Time
 }
 
 func NewArticleSearchRepository(client *Client) repository.ArticleSearchRepository {
-	return &articleSearchRepo{client: client}
+	return &articleSearchRepo{client: client, indexCreatedAt: map[string]time.Time{}}
 }
 
 // articleDocument: ESに保存する記事ドキュメントの構造体
 type articleDocument struct {
-	Id        int    `json:"id"`
-	Title     string `json:"title"`
-	Content   string `json:"content"`
-	Status    string `json:"status"`
-	CreatedAt string `json:"created_at"`
-	UpdatedAt string `json:"updated_at"`
+	Id        int      `json:"id"`
+	Title     string   `json:"title"`
+	Slug      string   `json:"slug"`
+	Content   string   `json:"content"`          // 検索用にMarkdownから取り出した本文
+	Markdown  string   `json:"content_markdown"` // 検索結果として返す元の本文(検索の対象外)
+	Status    string   `json:"status"`
+	Tags      []string `json:"tags"`
+	LikeCount int      `json:"like_count"` // 検索結果の並び替えに使う(いいね数の変更は定期的に反映する)
+	CreatedAt string   `json:"created_at"`
+	UpdatedAt string   `json:"updated_at"`
+}
+
+// newArticleDocument: ドメインモデルをESのドキュメントに変換
+func newArticleDocument(article *model.Article) articleDocument {
+	return articleDocument{
+		Id:        int(article.ID),
+		Title:     article.Title,
+		Slug:      article.Slug,
+		Content:   markdown.PlainText(article.Content),
+		Markdown:  article.Content,
+		Status:    article.Status,
+		Tags:      article.TagNames(),
+		LikeCount: article.LikeCount,
+		CreatedAt: article.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: article.UpdatedAt.Format(time.RFC3339),
+	}
 }
 
 // ConvertToModel: ESのレスポンスをドメインモデルに変換
@@ -43,8 +74,17 @@ func ConvertToModel(data articleDocument) *model.Article {
 
 	article.ID = uint(data.Id)
 	article.Title = data.Title
-	article.Content = data.Content
+	article.Slug = data.Slug
+	article.Content = data.Markdown
+	if article.Content == "" {
+		// 再インデックス前のドキュメントには元の本文がない
+		article.Content = data.Content
+	}
 	article.Status = data.Status
+	article.LikeCount = data.LikeCount
+	for _, name := range data.Tags {
+		article.Tags = append(article.Tags, model.Tag{Name: name})
+	}
 	article.CreatedAt, _ = time.Parse(time.RFC3339, data.CreatedAt)
 	article.UpdatedAt, _ = time.Parse(time.RFC3339, data.UpdatedAt)
 
@@ -52,15 +92,15 @@ func ConvertToModel(data articleDocument) *model.Article {
 }
 
 // Search: 検索クエリ実行
-func Search(es *Client, req *search.Request) ([]*model.Article, error) {
+func Search(ctx context.Context, es *Client, req *search.Request) ([]*model.Article, error) {
 	res, err := es.Typed.
 		Search().
 		Index(ArticleIndexName).
 		Request(req).
-		Do(context.TODO())
+		Do(ctx)
 
 	if err != nil {
-		return nil, fmt.Errorf("search request failed: %w", err)
+		return nil, apperror.Unavailable("search engine is unavailable", fmt.Errorf("search request failed: %w", err))
 	}
 
 	articles := []*model.Article{}
@@ -78,31 +118,45 @@ func Search(es *Client, req *search.Request) ([]*model.Article, error) {
 
 // CreateIndex: インデックス作成
 func (r *articleSearchRepo) CreateIndex() (string, error) {
-	newIndexName := fmt.Sprintf("article_%s", time.Now().Format("200601021504"))
+	createdAt := time.Now()
+	newIndexName := fmt.Sprintf("article_%s", createdAt.Format("200601021504"))
+
+	// 元の本文は検索結果として返すだけのため、インデックスしない
+	contentMarkdown := types.NewTextProperty()
+	contentMarkdown.Index = some.Bool(false)
 
 	err := r.client.CreateIndex(newIndexName, &create.Request{
 		Mappings: &types.TypeMapping{
 			Properties: map[string]types.Property{
-				"id":         types.NewKeywordProperty(),
-				"title":      types.NewTextProperty(),
-				"content":    types.NewTextProperty(),
-				"status":     types.NewKeywordProperty(),
-				"created_at": types.NewDateProperty(),
-				"updated_at": types.NewDateProperty(),
+				"id":               types.NewKeywordProperty(),
+				"title":            types.NewTextProperty(),
+				"slug":             types.NewKeywordProperty(),
+				"content":          types.NewTextProperty(),
+				"content_markdown": contentMarkdown,
+				"status":           types.NewKeywordProperty(),
+				"tags":             types.NewKeywordProperty(),
+				"like_count":       types.NewIntegerNumberProperty(),
+				"created_at":       types.NewDateProperty(),
+				"updated_at":       types.NewDateProperty(),
 			},
 		},
 	})
 	if err != nil {
-		log.Println("❌ インデックス作成失敗:", err)
+		logger.Error("❌ インデックス作成失敗", "error", err)
+		return newIndexName, err
 	}
-	return newIndexName, err
+
+	r.mu.Lock()
+	r.indexCreatedAt[newIndexName] = createdAt
+	r.mu.Unlock()
+	return newIndexName, nil
 }
 
 // DeleteIndex: インデックス削除
 func (r *articleSearchRepo) DeleteIndex(indexName string) error {
 	err := r.client.DeleteIndex(indexName)
 	if err != nil {
-		log.Println("❌ インデックス削除失敗:", err)
+		logger.Error("❌ インデックス削除失敗", "error", err)
 	}
 	return err
 }
@@ -111,35 +165,40 @@ func (r *articleSearchRepo) DeleteIndex(indexName string) error {
 func (r *articleSearchRepo) SwitchAlias(newIndexName string) error {
 	err := r.client.UpdateAlias(ArticleIndexName, newIndexName)
 	if err != nil {
-		log.Println("❌ エイリアス更新失敗:", err)
+		logger.Error("❌ エイリアス更新失敗", "error", err)
+		return err
 	}
-	return err
+
+	r.mu.Lock()
+	createdAt, ok := r.indexCreatedAt[newIndexName]
+	delete(r.indexCreatedAt, newIndexName)
+	r.mu.Unlock()
+	if ok {
+		reindexDuration.Observe(time.Since(createdAt).Seconds())
+	}
+	return nil
 }
 
 // Index: データの保存
-func (r *articleSearchRepo) Index(indexName *string, article *model.Article) error {
+func (r *articleSearchRepo) Index(ctx context.Context, indexName *string, article *model.Article) error {
 	// インデックス名の指定がなければ、エイリアスが付与されてるインデックスを使用
 	index := ArticleIndexName
 	if indexName != nil {
 		index = *indexName
 	}
 
-	document := articleDocument{
-		Id:        int(article.ID),
-		Title:     article.Title,
-		Content:   article.Content,
-		Status:    article.Status,
-		CreatedAt: article.CreatedAt.String(),
-		UpdatedAt: article.UpdatedAt.String(),
-	}
+	document := newArticleDocument(article)
 
 	_, err := r.client.Typed.
 		Index(index).
 		Id(strconv.Itoa(int(article.ID))).
 		Request(document).
-		Do(context.Background())
+		Do(ctx)
+	if err != nil {
+		return apperror.Unavailable("search engine is unavailable", fmt.Errorf("index request failed: %w", err))
+	}
 
-	return err
+	return nil
 }
 
 // BulkIndex: データの一括保存
@@ -153,14 +212,7 @@ func (r *articleSearchRepo) BulkIndex(indexName *string, articles []*model.Artic
 	bulkRequest := r.client.Typed.Bulk()
 
 	for _, article := range articles {
-		document := articleDocument{
-			Id:        int(article.ID),
-			Title:     article.Title,
-			Content:   article.Content,
-			Status:    article.Status,
-			CreatedAt: article.CreatedAt.Format(time.RFC3339),
-			UpdatedAt: article.UpdatedAt.Format(time.RFC3339),
-		}
+		document := newArticleDocument(article)
 
 		id := strconv.Itoa(int(article.ID))
 		err := bulkRequest.IndexOp(
@@ -177,8 +229,19 @@ func (r *articleSearchRepo) BulkIndex(indexName *string, articles []*model.Artic
 
 	res, err := bulkRequest.Do(context.Background())
 	if err != nil {
-		return fmt.Errorf("bulk request failed: %w", err)
+		return apperror.Unavailable("search engine is unavailable", fmt.Errorf("bulk request failed: %w", err))
+	}
+
+	failed := 0
+	for _, item := range res.Items {
+		for _, result := range item {
+			if result.Error != nil {
+				failed++
+			}
+		}
 	}
+	bulkDocuments.WithLabelValues("success").Add(float64(len(res.Items) - failed))
+	bulkDocuments.WithLabelValues("failure").Add(float64(failed))
 
 	if res.Errors {
 		errorDetails := ""
@@ -200,38 +263,159 @@ func (r *articleSearchRepo) BulkIndex(indexName *string, articles []*model.Artic
 }
 
 // Delete: ドキュメント削除
-func (r *articleSearchRepo) Delete(id int64) error {
+func (r *articleSearchRepo) Delete(ctx context.Context, id int64) error {
 	articleId := strconv.Itoa(int(id))
 	_, err := r.client.Typed.
 		Delete(ArticleIndexName, articleId).
-		Do(context.Background())
+		Do(ctx)
+	if err != nil {
+		return apperror.Unavailable("search engine is unavailable", fmt.Errorf("delete request failed: %w", err))
+	}
 
-	return err
+	return nil
+}
+
+// UpdateLikeCounts: いいね数の一括更新
+// インデックスにない記事(非公開化の途中など)は次回の登録時に反映されるため、エラーにしない
+func (r *articleSearchRepo) UpdateLikeCounts(ctx context.Context, counts []repository.LikeCount) error {
+	bulkRequest := r.client.Typed.Bulk().Index(ArticleIndexName)
+	for _, count := range counts {
+		id := strconv.Itoa(int(count.ArticleID))
+		doc, err := json.Marshal(map[string]int{"like_count": count.Count})
+		if err != nil {
+			return err
+		}
+		if err := bulkRequest.UpdateOp(types.UpdateOperation{Id_: &id}, doc, nil); err != nil {
+			return fmt.Errorf("failed to add operation: %w", err)
+		}
+	}
+
+	res, err := bulkRequest.Do(ctx)
+	if err != nil {
+		return apperror.Unavailable("search engine is unavailable", fmt.Errorf("bulk request failed: %w", err))
+	}
+	if !res.Errors {
+		return nil
+	}
+
+	errorDetails := ""
+	for _, item := range res.Items {
+		for op, result := range item {
+			if result.Error == nil || result.Status == http.StatusNotFound {
+				continue
+			}
+			errorReason := ""
+			if result.Error.Reason != nil {
+				errorReason = *result.Error.Reason
+			}
+			errorDetails += fmt.Sprintf("Operation: %s, Status: %d, Error: %s\n", op, result.Status, errorReason)
+		}
+	}
+	if errorDetails != "" {
+		return fmt.Errorf("bulk request had item errors:\n%s", errorDetails)
+	}
+	return nil
 }
 
-// SimpleSearch: キーワード検索
-func (r *articleSearchRepo) SimpleSearch(keyword string) ([]*model.Article, error) {
+// SimpleSearch: キーワードとタグで公開記事を検索
+// キーワードの関連度にいいね数によるスコアを加え、いいねの多い記事ほど上位にする
+// キーワードが空の場合は、タグの指定にかかわらず何もヒットしない
+func (r *articleSearchRepo) SimpleSearch(ctx context.Context, query repository.ArticleSearchQuery) ([]*model.Article, error) {
+	if strings.TrimSpace(query.Keyword) == "" {
+		return []*model.Article{}, nil
+	}
+
+	boolQuery := &types.BoolQuery{
+		Filter: publishedArticleFilters(query.Tags),
+		// キーワード検索(関連度をスコアに含める)
+		Must: []types.Query{
+			{
+				MultiMatch: &types.MultiMatchQuery{
+					Query:  query.Keyword,
+					Fields: []string{"title", "content"},
+				},
+			},
+		},
+	}
+
 	req := &search.Request{
 		Query: &types.Query{
-			Bool: &types.BoolQuery{
-				Filter: []types.Query{
+			FunctionScore: &types.FunctionScoreQuery{
+				Query: &types.Query{Bool: boolQuery},
+				Functions: []types.FunctionScore{
 					{
-						// 公開ステータスの絞り込み
-						Term: map[string]types.TermQuery{
-							"status": {Value: "published"},
-						},
-					},
-					{
-						// キーワード検索
-						MultiMatch: &types.MultiMatchQuery{
-							Query:  keyword,
-							Fields: []string{"title", "content"},
+						// ln(1 + いいね数): いいねが増えるほど上がり幅は小さくなる
+						FieldValueFactor: &types.FieldValueFactorScoreFunction{
+							Field:    "like_count",
+							Modifier: &fieldvaluefactormodifier.Ln1p,
+							Missing:  some.Float64(0),
 						},
 					},
 				},
+				BoostMode: &functionboostmode.Sum,
 			},
 		},
 	}
 
-	return Search(r.client, req)
+	return Search(ctx, r.client, req)
+}
+
+// PopularTags: 公開記事に多く付けられているタグを集計
+func (r *articleSearchRepo) PopularTags(ctx context.Context, size int) ([]model.TagCount, error) {
+	const aggregationName = "popular_tags"
+
+	res, err := r.client.Typed.
+		Search().
+		Index(ArticleIndexName).
+		Request(&search.Request{
+			Size: some.Int(0),
+			Query: &types.Query{
+				Bool: &types.BoolQuery{Filter: publishedArticleFilters(nil)},
+			},
+			Aggregations: map[string]types.Aggregations{
+				aggregationName: {
+					Terms: &types.TermsAggregation{
+						Field: some.String("tags"),
+						Size:  &size,
+					},
+				},
+			},
+		}).
+		Do(ctx)
+	if err != nil {
+		return nil, apperror.Unavailable("search engine is unavailable", fmt.Errorf("aggregation request failed: %w", err))
+	}
+
+	tagCounts := []model.TagCount{}
+	terms, ok := res.Aggregations[aggregationName].(*types.StringTermsAggregate)
+	if !ok {
+		return tagCounts, nil
+	}
+	buckets, _ := terms.Buckets.([]types.StringTermsBucket)
+	for _, bucket := range buckets {
+		tagCounts = append(tagCounts, model.TagCount{
+			Name:  fmt.Sprint(bucket.Key),
+			Count: bucket.DocCount,
+		})
+	}
+	return tagCounts, nil
+}
+
+// publishedArticleFilters: 公開ステータスと、指定したタグをすべて含む記事に絞り込む条件
+func publishedArticleFilters(tags []string) []types.Query {
+	filters := []types.Query{
+		{
+			Term: map[string]types.TermQuery{
+				"status": {Value: "published"},
+			},
+		},
+	}
+	for _, tag := range tags {
+		filters = append(filters, types.Query{
+			Term: map[string]types.TermQuery{
+				"tags": {Value: tag},
+			},
+		})
+	}
+	return filters
 }
@@ -0,0 +1,26 @@
+package es
+
+import (
+	"context"
+	"testing"
+
+	"elasticsearch-sample/backend/internal/domain/repository"
+)
+
+func TestSimpleSearchEmptyKeyword(t *testing.T) {
+	// クライアントがnilでも、検索エンジンに問い合わせずに空の結果を返す
+	repo := NewArticleSearchRepository(nil)
+
+	for _, keyword := range []string{"", "  \t\n"} {
+		articles, err := repo.SimpleSearch(context.Background(), repository.ArticleSearchQuery{
+			Keyword: keyword,
+			Tags:    []string{"go"},
+		})
+		if err != nil {
+			t.Fatalf("SimpleSearch(%q) error = %v", keyword, err)
+		}
+		if articles == nil || len(articles) != 0 {
+			t.Errorf("SimpleSearch(%q) = %v, want empty", keyword, articles)
+		}
+	}
+}
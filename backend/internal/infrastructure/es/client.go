@@ -2,28 +2,33 @@ package es
 
 import (
 	"context"
+	"elasticsearch-sample/backend/internal/config"
+	"errors"
 	"fmt"
-	"log"
-	"os"
 
 	elasticsearch "github.com/elastic/go-elasticsearch/v9"
+	"github.com/elastic/go-elasticsearch/v9/typedapi/types/enums/healthstatus"
 )
 
 type Client struct {
 	Typed *elasticsearch.TypedClient
 }
 
-// NewClient は環境変数から設定を読み込み、ESクライアントを初期化します
-func NewClient() (*Client, error) {
-	// 接続設定
-	cfg := elasticsearch.Config{
-		Addresses: []string{os.Getenv("ES_HOST")},
-		CloudID:   os.Getenv("ES_CLOUD_ID"),
-		APIKey:    os.Getenv("ES_API_KEY"),
-	}
-
+// NewClient は設定に従ってESクライアントを初期化します
+func NewClient(cfg config.ElasticsearchConfig) (*Client, error) {
 	// v9 の TypedClient を作成
-	typedClient, err := elasticsearch.NewTypedClient(cfg)
+	typedClient, err := elasticsearch.NewTypedClient(elasticsearch.Config{
+		Addresses: cfg.Addresses,
+		CloudID:   cfg.CloudID,
+		APIKey:    cfg.APIKey.Value(),
+		// エンドポイントごとのレイテンシをメトリクスとして記録し、リクエストごとにスパンを作成する
+		// (検索クエリの本文はスパンに含めない)
+		Instrumentation: multiInstrumentation{
+			metricsInstrumentation{},
+			elasticsearch.NewOpenTelemetryInstrumentation(nil, false),
+		},
+		Logger: requestLogger{slowThreshold: cfg.SlowRequestThreshold},
+	})
 	if err != nil {
 		return nil, fmt.Errorf("❌ Elasticsearch typed client作成失敗: %w", err)
 	}
@@ -32,10 +37,35 @@ func NewClient() (*Client, error) {
 	// v9 では Ping() もメソッドチェーンで呼び出せます
 	_, err = typedClient.Info().Do(context.Background())
 	if err != nil {
-		return nil, fmt.Errorf("❌ Elasticsearch接続失敗: %w", err)
+		return nil, errors.Join(fmt.Errorf("❌ Elasticsearch接続失敗: %w", err), typedClient.Close(context.Background()))
 	}
 
-	log.Println("✅ Elasticsearch接続が成功しました")
+	logger.Info("✅ Elasticsearch接続が成功しました")
 
 	return &Client{Typed: typedClient}, nil
 }
+
+// Close: 接続を閉じる
+func (c *Client) Close(ctx context.Context) error {
+	return c.Typed.Close(ctx)
+}
+
+// Ping: クラスタの状態がredでないこと、指定したエイリアスが存在することを確認する
+func (c *Client) Ping(ctx context.Context, aliasName string) error {
+	health, err := c.Typed.Cluster.Health().Do(ctx)
+	if err != nil {
+		return fmt.Errorf("クラスタの状態の取得に失敗しました: %w", err)
+	}
+	if health.Status == healthstatus.Red {
+		return fmt.Errorf("クラスタの状態が %s です", health.Status)
+	}
+
+	exists, err := c.Typed.Indices.ExistsAlias(aliasName).Do(ctx)
+	if err != nil {
+		return fmt.Errorf("エイリアスの確認に失敗しました: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("エイリアス '%s' が存在しません", aliasName)
+	}
+	return nil
+}
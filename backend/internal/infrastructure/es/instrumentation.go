@@ -0,0 +1,67 @@
+package es
+
+import (
+	"context"
+	"io"
+	"net/http"
+
+	"github.com/elastic/elastic-transport-go/v8/elastictransport"
+)
+
+// multiInstrumentation 複数のInstrumentation(メトリクスとトレース)をまとめてクライアントに設定する
+type multiInstrumentation []elastictransport.Instrumentation
+
+var _ elastictransport.Instrumentation = multiInstrumentation{}
+
+func (m multiInstrumentation) Start(ctx context.Context, name string) context.Context {
+	for _, i := range m {
+		ctx = i.Start(ctx, name)
+	}
+	return ctx
+}
+
+func (m multiInstrumentation) Close(ctx context.Context) {
+	for _, i := range m {
+		i.Close(ctx)
+	}
+}
+
+func (m multiInstrumentation) RecordError(ctx context.Context, err error) {
+	for _, i := range m {
+		i.RecordError(ctx, err)
+	}
+}
+
+func (m multiInstrumentation) RecordPathPart(ctx context.Context, pathPart, value string) {
+	for _, i := range m {
+		i.RecordPathPart(ctx, pathPart, value)
+	}
+}
+
+// RecordRequestBody ボディを読み込むのは最初の1つだけ (読み込むと後続に渡せないため)
+func (m multiInstrumentation) RecordRequestBody(ctx context.Context, endpoint string, query io.Reader) io.ReadCloser {
+	for _, i := range m {
+		if reader := i.RecordRequestBody(ctx, endpoint, query); reader != nil {
+			return reader
+		}
+	}
+	return nil
+}
+
+func (m multiInstrumentation) BeforeRequest(req *http.Request, endpoint string) {
+	for _, i := range m {
+		i.BeforeRequest(req, endpoint)
+	}
+}
+
+func (m multiInstrumentation) AfterRequest(req *http.Request, system, endpoint string) {
+	for _, i := range m {
+		i.AfterRequest(req, system, endpoint)
+	}
+}
+
+func (m multiInstrumentation) AfterResponse(ctx context.Context, res *http.Response) {
+	for _, i := range m {
+		i.AfterResponse(ctx, res)
+	}
+}
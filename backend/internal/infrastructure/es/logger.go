@@ -0,0 +1,63 @@
+package es
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/elastic/elastic-transport-go/v8/elastictransport"
+
+	"elasticsearch-sample/backend/internal/logging"
+)
+
+var logger = logging.Component("es")
+
+// requestLogger Elasticsearchへのリクエストをslogに出力する
+// 失敗したリクエストはERROR、時間のかかったリクエストはWARN、それ以外はDEBUGで出力する
+type requestLogger struct {
+	slowThreshold time.Duration // 0の場合は警告しない
+}
+
+var _ elastictransport.Logger = requestLogger{}
+
+func (l requestLogger) LogRoundTrip(req *http.Request, res *http.Response, err error, start time.Time, duration time.Duration) error {
+	level := slog.LevelDebug
+	switch {
+	case err != nil || (res != nil && res.StatusCode >= http.StatusInternalServerError):
+		level = slog.LevelError
+	case l.slowThreshold > 0 && duration > l.slowThreshold:
+		level = slog.LevelWarn
+	}
+
+	ctx := req.Context()
+	if !logger.Enabled(ctx, level) {
+		return nil
+	}
+
+	args := []any{
+		"method", req.Method,
+		"path", req.URL.Path,
+		"duration_ms", duration.Milliseconds(),
+	}
+	if res != nil {
+		args = append(args, "status", res.StatusCode)
+	}
+	if err != nil {
+		args = append(args, "error", err)
+	}
+
+	msg := "Elasticsearchリクエスト"
+	switch level {
+	case slog.LevelError:
+		msg = "❌ Elasticsearchへのリクエストに失敗しました"
+	case slog.LevelWarn:
+		msg = "⚠️ Elasticsearchへのリクエストに時間がかかりました"
+	}
+	logger.Log(ctx, level, msg, args...)
+	return nil
+}
+
+// RequestBodyEnabled 検索クエリの本文はログに含めない
+func (requestLogger) RequestBodyEnabled() bool { return false }
+
+func (requestLogger) ResponseBodyEnabled() bool { return false }
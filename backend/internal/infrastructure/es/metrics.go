@@ -0,0 +1,90 @@
+package es
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/elastic/elastic-transport-go/v8/elastictransport"
+	"github.com/prometheus/client_golang/prometheus"
+
+	"elasticsearch-sample/backend/internal/metrics"
+)
+
+var (
+	requestDuration = metrics.Factory.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "es_request_duration_seconds",
+		Help:    "Elasticsearchへのリクエストの所要時間",
+		Buckets: metrics.LatencyBuckets,
+	}, []string{"endpoint", "status"})
+
+	bulkDocuments = metrics.Factory.NewCounterVec(prometheus.CounterOpts{
+		Name: "es_bulk_documents_total",
+		Help: "Bulk APIで登録したドキュメント数",
+	}, []string{"result"})
+
+	reindexDuration = metrics.Factory.NewHistogram(prometheus.HistogramOpts{
+		Name:    "es_reindex_duration_seconds",
+		Help:    "インデックスの作成からエイリアスの切り替えまでの所要時間",
+		Buckets: prometheus.ExponentialBuckets(1, 2, 12), // 1秒〜約34分
+	})
+)
+
+type requestMetricsKey struct{}
+
+// requestMetrics 1リクエスト分の計測情報
+type requestMetrics struct {
+	endpoint string
+	start    time.Time
+
+	mu     sync.Mutex
+	status string // ステータスコード(レスポンスがない場合は"error")
+}
+
+// metricsInstrumentation エンドポイントごとのレイテンシとステータスを記録する
+// elastictransport.Instrumentationとしてクライアントに設定する
+type metricsInstrumentation struct{}
+
+var _ elastictransport.Instrumentation = metricsInstrumentation{}
+
+func (metricsInstrumentation) Start(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, requestMetricsKey{}, &requestMetrics{endpoint: name, start: time.Now()})
+}
+
+func (metricsInstrumentation) Close(ctx context.Context) {
+	m, ok := ctx.Value(requestMetricsKey{}).(*requestMetrics)
+	if !ok {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	status := m.status
+	if status == "" {
+		status = "error"
+	}
+	requestDuration.WithLabelValues(m.endpoint, status).Observe(time.Since(m.start).Seconds())
+}
+
+func (metricsInstrumentation) RecordError(ctx context.Context, err error) {}
+
+func (metricsInstrumentation) RecordPathPart(ctx context.Context, pathPart, value string) {}
+
+func (metricsInstrumentation) RecordRequestBody(ctx context.Context, endpoint string, query io.Reader) io.ReadCloser {
+	return nil
+}
+
+func (metricsInstrumentation) BeforeRequest(req *http.Request, endpoint string) {}
+
+func (metricsInstrumentation) AfterRequest(req *http.Request, system, endpoint string) {}
+
+// AfterResponse ステータスコードを記録する
+func (metricsInstrumentation) AfterResponse(ctx context.Context, res *http.Response) {
+	if m, ok := ctx.Value(requestMetricsKey{}).(*requestMetrics); ok && res != nil {
+		m.mu.Lock()
+		m.status = strconv.Itoa(res.StatusCode)
+		m.mu.Unlock()
+	}
+}
@@ -0,0 +1,56 @@
+package pubsub
+
+import (
+	"context"
+	"sync"
+
+	"elasticsearch-sample/backend/internal/logging"
+)
+
+var logger = logging.Component("pubsub")
+
+// 購読者ごとのバッファサイズ(溢れたイベントは破棄される)
+const subscriberBufferSize = 16
+
+// Broker プロセス内でイベントを購読者に配信するPub/Sub
+type Broker[T any] struct {
+	mu          sync.RWMutex
+	subscribers map[chan T]struct{}
+}
+
+func NewBroker[T any]() *Broker[T] {
+	return &Broker[T]{subscribers: map[chan T]struct{}{}}
+}
+
+// Publish: 全ての購読者にイベントを配信する(受信が遅い購読者への配信は破棄)
+func (b *Broker[T]) Publish(ctx context.Context, event T) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			logger.WarnContext(ctx, "⚠️ 購読者のバッファが溢れたためイベントを破棄しました")
+		}
+	}
+}
+
+// Subscribe: ctxがキャンセルされるまでイベントを受け取るチャネルを返す
+func (b *Broker[T]) Subscribe(ctx context.Context) <-chan T {
+	ch := make(chan T, subscriberBufferSize)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		b.mu.Lock()
+		delete(b.subscribers, ch)
+		b.mu.Unlock()
+		close(ch)
+	}()
+
+	return ch
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/config"
+	"elasticsearch-sample/backend/internal/domain/repository"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// LocalStore サーバーのファイルシステムにファイルを保存し、HTTPで配信する
+// 開発環境専用 (配信時に記事の公開状態を確認しないため、下書きやゴミ箱の記事の添付ファイルもURLを知っていれば取得できる)
+// 本番環境では設定の検証で使用を禁止している
+type LocalStore struct {
+	root    *os.Root // ディレクトリの外のファイルにはアクセスできない
+	baseURL string
+	mount   string // ファイルを配信するパス (baseURLのパスの部分)
+}
+
+var _ repository.BlobStore = (*LocalStore)(nil)
+var _ http.Handler = (*LocalStore)(nil)
+
+func NewLocalStore(cfg config.LocalStorageConfig) (*LocalStore, error) {
+	if err := os.MkdirAll(cfg.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("❌ 保存先ディレクトリの作成に失敗しました: %w", err)
+	}
+	root, err := os.OpenRoot(cfg.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("❌ 保存先ディレクトリを開けません: %w", err)
+	}
+	u, err := url.Parse(cfg.BaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("❌ storage.local.base_url が不正です: %w", err)
+	}
+
+	logger.Info("📁 添付ファイルをローカルに保存します", "dir", cfg.Dir)
+	return &LocalStore{
+		root:    root,
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		mount:   strings.TrimSuffix(u.Path, "/") + "/",
+	}, nil
+}
+
+// MountPath ServeHTTPでファイルを配信するパス (末尾は/)
+func (s *LocalStore) MountPath() string {
+	return s.mount
+}
+
+func (s *LocalStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if !filepath.IsLocal(key) {
+		return fmt.Errorf("不正なキーです: %q", key)
+	}
+	if err := s.mkdirAll(filepath.Dir(key)); err != nil {
+		return err
+	}
+	f, err := s.root.Create(key)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(f, r); err != nil {
+		f.Close()
+		// 書き込み途中のファイルは残さない
+		_ = s.root.Remove(key)
+		return err
+	}
+	return f.Close()
+}
+
+// mkdirAll dirとその親ディレクトリをrootの中に作成する
+// os.Root.MkdirAllはGo 1.25以降のため、1階層ずつMkdirする
+func (s *LocalStore) mkdirAll(dir string) error {
+	if dir == "." {
+		return nil
+	}
+	if err := s.mkdirAll(filepath.Dir(dir)); err != nil {
+		return err
+	}
+	if err := s.root.Mkdir(dir, 0o755); err != nil && !errors.Is(err, fs.ErrExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	if err := s.root.Remove(key); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *LocalStore) URL(ctx context.Context, key string) (string, error) {
+	return s.baseURL + "/" + key, nil
+}
+
+func (s *LocalStore) Ping(ctx context.Context) error {
+	_, err := s.root.Stat(".")
+	return err
+}
+
+// ServeHTTP MountPathを取り除いたパスのファイルを返す (ディレクトリの一覧は返さない)
+// 記事の公開状態は確認しない (LocalStoreは開発環境専用)
+func (s *LocalStore) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	f, err := s.root.Open(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	// アップロード時に検証した形式以外として解釈させない
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	http.ServeContent(w, r, info.Name(), info.ModTime(), f)
+}
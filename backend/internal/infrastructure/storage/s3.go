@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/config"
+	"elasticsearch-sample/backend/internal/domain/repository"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// 起動時にバケットを確認する際のタイムアウト
+const s3ConnectTimeout = 10 * time.Second
+
+// S3Store S3互換のオブジェクトストレージ(AWS S3、MinIOなど)にファイルを保存する
+type S3Store struct {
+	client  *minio.Client
+	bucket  string
+	baseURL string        // 空の場合は署名付きURLを返す
+	expiry  time.Duration // 署名付きURLの有効期間
+}
+
+var _ repository.BlobStore = (*S3Store)(nil)
+
+func NewS3Store(cfg config.S3StorageConfig) (*S3Store, error) {
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey.Value(), ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("❌ S3クライアント作成失敗: %w", err)
+	}
+
+	// バケットがなければ作成する (ローカルのMinIOで使う場合など)
+	ctx, cancel := context.WithTimeout(context.Background(), s3ConnectTimeout)
+	defer cancel()
+	exists, err := client.BucketExists(ctx, cfg.Bucket)
+	if err != nil {
+		return nil, fmt.Errorf("❌ S3接続失敗: %w", err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, cfg.Bucket, minio.MakeBucketOptions{Region: cfg.Region}); err != nil {
+			return nil, fmt.Errorf("❌ バケット作成失敗: %w", err)
+		}
+		logger.Info("🪣 バケットを作成しました", "bucket", cfg.Bucket)
+	}
+
+	logger.Info("✅ S3接続が成功しました", "endpoint", cfg.Endpoint, "bucket", cfg.Bucket)
+	return &S3Store{
+		client:  client,
+		bucket:  cfg.Bucket,
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		expiry:  cfg.PresignedExpiry,
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType:  contentType,
+		CacheControl: "public, max-age=31536000, immutable",
+	})
+	return err
+}
+
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	return s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{})
+}
+
+func (s *S3Store) URL(ctx context.Context, key string) (string, error) {
+	if s.baseURL != "" {
+		return s.baseURL + "/" + key, nil
+	}
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, s.expiry, nil)
+	if err != nil {
+		return "", err
+	}
+	return u.String(), nil
+}
+
+func (s *S3Store) Ping(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return fmt.Errorf("バケット %s が存在しません", s.bucket)
+	}
+	return nil
+}
@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/config"
+	"elasticsearch-sample/backend/internal/domain/repository"
+	"elasticsearch-sample/backend/internal/logging"
+	"errors"
+	"fmt"
+	"io"
+)
+
+var logger = logging.Component("storage")
+
+// New 設定に従って添付ファイルの保存先を初期化する
+func New(cfg config.StorageConfig) (repository.BlobStore, error) {
+	switch cfg.Backend {
+	case "local":
+		return NewLocalStore(cfg.Local)
+	case "s3":
+		return NewS3Store(cfg.S3)
+	default:
+		return nil, fmt.Errorf("❌ 不明なストレージです: %q", cfg.Backend)
+	}
+}
+
+// ErrDisabled 保存先を初期化していない場合のエラー
+var ErrDisabled = errors.New("添付ファイルの保存先は無効です")
+
+// disabledStore 添付ファイルを扱わないコマンドで使う保存先 (すべての操作がErrDisabledになる)
+type disabledStore struct{}
+
+// Disabled 何も保存しない保存先を返す
+// シードや再インデックスなど、添付ファイルを扱わないコマンドでS3のバケットなどを作成しないために使う
+func Disabled() repository.BlobStore {
+	return disabledStore{}
+}
+
+func (disabledStore) Put(context.Context, string, io.Reader, int64, string) error { return ErrDisabled }
+func (disabledStore) Delete(context.Context, string) error                        { return ErrDisabled }
+func (disabledStore) URL(context.Context, string) (string, error)                 { return "", ErrDisabled }
+func (disabledStore) Ping(context.Context) error                                  { return ErrDisabled }
@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+// RequestIDKey リクエストIDの属性のキー
+const RequestIDKey = "request_id"
+
+type loggerKey struct{}
+
+type requestIDKey struct{}
+
+// NewContext ロガーをContextに入れる
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext Contextのロガーを取り出す (入っていない場合はデフォルトのロガー)
+// *Context系のメソッドで出力すると、リクエストIDとトレースIDが付く
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// WithRequestID リクエストIDをContextに入れる
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext ContextのリクエストIDを取り出す
+func RequestIDFromContext(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(requestIDKey{}).(string)
+	return id, ok && id != ""
+}
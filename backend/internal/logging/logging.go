@@ -0,0 +1,150 @@
+package logging
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"elasticsearch-sample/backend/internal/config"
+)
+
+// ComponentKey ログの出力元を表す属性のキー (コンポーネントごとのレベルの判定に使う)
+const ComponentKey = "component"
+
+// output Setupで設定された出力先とレベル
+type output struct {
+	handler    slog.Handler
+	level      slog.Level
+	components map[string]slog.Level
+}
+
+// levelFor コンポーネントのレベル (個別の指定がない場合は全体のレベル)
+func (o *output) levelFor(component string) slog.Level {
+	if level, ok := o.components[component]; ok {
+		return level
+	}
+	return o.level
+}
+
+var current atomic.Pointer[output]
+
+func init() {
+	current.Store(&output{handler: newHandler(os.Stderr, "text")})
+}
+
+// Setup 設定に従ってログの出力形式とレベルを設定し、slogのデフォルトのロガーにする
+// logパッケージの出力もslog経由(INFOレベル)になる
+func Setup(cfg config.LogConfig) {
+	o := &output{
+		handler:    newHandler(os.Stdout, cfg.Format),
+		level:      parseLevel(cfg.Level),
+		components: make(map[string]slog.Level, len(cfg.Components)),
+	}
+	for component, level := range cfg.Components {
+		o.components[component] = parseLevel(level)
+	}
+	current.Store(o)
+	slog.SetDefault(slog.New(&handler{}))
+}
+
+func newHandler(w io.Writer, format string) slog.Handler {
+	// レベルの判定はhandlerで行うため、出力先ではすべて出力する
+	options := &slog.HandlerOptions{Level: slog.LevelDebug}
+	if format == "text" {
+		return slog.NewTextHandler(w, options)
+	}
+	return slog.NewJSONHandler(w, options)
+}
+
+func parseLevel(s string) slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
+
+// Component コンポーネント名を付けたロガー
+// パッケージ変数として初期化しても、Setup後の設定で出力される
+func Component(name string) *slog.Logger {
+	return slog.New(&handler{}).With(ComponentKey, name)
+}
+
+// Fatal エラーを出力して終了する (コマンドの起動処理用)
+func Fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+// handler Setupで設定された出力先に、Contextのリクエスト情報を付けて出力する
+// With/WithGroupは出力時に現在の出力先に適用する
+type handler struct {
+	component    string
+	hasRequestID bool
+	grouped      bool
+	ops          []func(slog.Handler) slog.Handler
+}
+
+var _ slog.Handler = (*handler)(nil)
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= current.Load().levelFor(h.component)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.hasRequestID {
+		if id, ok := RequestIDFromContext(ctx); ok {
+			r.AddAttrs(slog.String(RequestIDKey, id))
+		}
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		r.AddAttrs(
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		)
+	}
+
+	out := current.Load().handler
+	for _, op := range h.ops {
+		out = op(out)
+	}
+	return out.Handle(ctx, r)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := h.with(func(out slog.Handler) slog.Handler { return out.WithAttrs(attrs) })
+	// グループ内の属性はコンポーネントやリクエストIDとして扱わない
+	if !h.grouped {
+		for _, attr := range attrs {
+			switch attr.Key {
+			case ComponentKey:
+				next.component = attr.Value.String()
+			case RequestIDKey:
+				next.hasRequestID = true
+			}
+		}
+	}
+	return next
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	next := h.with(func(out slog.Handler) slog.Handler { return out.WithGroup(name) })
+	next.grouped = true
+	return next
+}
+
+func (h *handler) with(op func(slog.Handler) slog.Handler) *handler {
+	return &handler{
+		component:    h.component,
+		hasRequestID: h.hasRequestID,
+		grouped:      h.grouped,
+		ops:          append(h.ops[:len(h.ops):len(h.ops)], op),
+	}
+}
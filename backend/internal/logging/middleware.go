@@ -0,0 +1,71 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+
+	"github.com/felixge/httpsnoop"
+)
+
+// RequestIDHeader リクエストIDを受け取り・返却するヘッダー
+const RequestIDHeader = "X-Request-ID"
+
+// 受け取ったリクエストIDとして使う長さの上限
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware リクエストIDをContextとレスポンスヘッダーに設定し、リクエストごとにアクセスログを出力する
+// 呼び出し元(ロードバランサーなど)が付けたX-Request-IDがあれば引き継ぐ
+func RequestIDMiddleware(next http.Handler) http.Handler {
+	logger := Component("http")
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(RequestIDHeader, id)
+
+		ctx := NewContext(WithRequestID(r.Context(), id), logger)
+		r = r.WithContext(ctx)
+
+		m := httpsnoop.CaptureMetrics(next, w, r)
+
+		level := slog.LevelInfo
+		switch {
+		case m.Code >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case r.URL.Path == "/healthz" || r.URL.Path == "/readyz" || r.URL.Path == "/metrics":
+			// 定期的に呼ばれるため、通常は出力しない
+			level = slog.LevelDebug
+		}
+		logger.Log(ctx, level, "HTTPリクエスト",
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", m.Code,
+			"duration_ms", m.Duration.Milliseconds(),
+			"bytes", m.Written,
+		)
+	})
+}
+
+// validRequestID ログに出力しても問題のない文字だけで構成されているか
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9', c == '-', c == '_', c == '.':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -0,0 +1,111 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+const (
+	charsPerMinute = 500 // 日本語を1分間に読める文字数
+	wordsPerMinute = 200 // 英語などを1分間に読める単語数
+)
+
+var (
+	// GitHub Flavored Markdown(表・取り消し線・URLの自動リンクなど)に対応する
+	// 生のHTMLは出力しない(goldmarkの既定の動作)
+	md = goldmark.New(goldmark.WithExtensions(extension.GFM, extension.CJK))
+
+	// ユーザーが書いた内容として安全なタグ・属性のみ残す
+	policy = bluemonday.UGCPolicy()
+)
+
+// HTML MarkdownをサニタイズしたHTMLに変換する
+func HTML(source string) string {
+	var buf bytes.Buffer
+	if err := md.Convert([]byte(source), &buf); err != nil {
+		// 書き込み先がメモリのため通常は発生しないが、その場合はエスケープした本文を返す
+		return policy.Sanitize(source)
+	}
+	return policy.SanitizeReader(&buf).String()
+}
+
+// PlainText Markdownから本文の文字列だけを取り出す
+// 記法の記号やリンク先のURL、HTMLタグは含めず、ブロックごとに改行で区切る
+func PlainText(source string) string {
+	src := []byte(source)
+	doc := md.Parser().Parse(text.NewReader(src))
+
+	var b strings.Builder
+	_ = ast.Walk(doc, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			if n.Type() == ast.TypeBlock {
+				b.WriteByte('\n')
+			}
+			return ast.WalkContinue, nil
+		}
+
+		switch n := n.(type) {
+		case *ast.Text:
+			b.Write(n.Segment.Value(src))
+			if n.SoftLineBreak() || n.HardLineBreak() {
+				b.WriteByte('\n')
+			}
+		case *ast.String:
+			b.Write(n.Value)
+		case *ast.CodeBlock, *ast.FencedCodeBlock:
+			lines := n.Lines()
+			for i := 0; i < lines.Len(); i++ {
+				segment := lines.At(i)
+				b.Write(segment.Value(src))
+			}
+		case *ast.AutoLink, *ast.RawHTML, *ast.HTMLBlock:
+			// URLやHTMLタグは検索の対象にしない
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+
+	// 空行と行頭・行末の空白を除く
+	var lines []string
+	for _, line := range strings.Split(b.String(), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Excerpt 本文の文字列を1行にまとめ、maxRunes文字を超える場合は省略する
+func Excerpt(plainText string, maxRunes int) string {
+	s := strings.Join(strings.Fields(plainText), " ")
+	if utf8.RuneCountInString(s) <= maxRunes {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimSpace(string(runes[:maxRunes])) + "…"
+}
+
+// ReadingMinutes 本文の文字列を読むのにかかるおおよその時間(分、最低1分)
+// 日本語は文字数、それ以外は単語数から計算する
+func ReadingMinutes(plainText string) int {
+	cjkChars := 0
+	others := strings.Map(func(r rune) rune {
+		if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) {
+			cjkChars++
+			return ' '
+		}
+		return r
+	}, plainText)
+	words := len(strings.Fields(others))
+
+	minutes := float64(cjkChars)/charsPerMinute + float64(words)/wordsPerMinute
+	return max(1, int(minutes+0.999))
+}
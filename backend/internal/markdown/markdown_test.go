@@ -0,0 +1,151 @@
+package markdown
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlainText(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		want   string
+	}{
+		{
+			name:   "emphasis and headings",
+			source: "# Title\n\nSome **bold** and _italic_ text.",
+			want:   "Title\nSome bold and italic text.",
+		},
+		{
+			name:   "link keeps text without url",
+			source: "See [the docs](https://example.com/docs) for details.",
+			want:   "See the docs for details.",
+		},
+		{
+			name:   "autolink is dropped",
+			source: "Visit <https://example.com> or https://example.org today.",
+			want:   "Visit  or  today.",
+		},
+		{
+			name:   "raw html is dropped",
+			source: "Hello <span class=\"x\">world</span>\n\n<div>\nblock html\n</div>\n\nAfter",
+			want:   "Hello world\nAfter",
+		},
+		{
+			name:   "code fence keeps code",
+			source: "Before\n\n```go\nfmt.Println(\"hi\")\n```\n\nAfter",
+			want:   "Before\nfmt.Println(\"hi\")\nAfter",
+		},
+		{
+			name:   "list items are separate lines",
+			source: "- one\n- two\n",
+			want:   "one\ntwo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := PlainText(tt.source); got != tt.want {
+				t.Errorf("PlainText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLSanitizes(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  string
+		wantNot []string
+		want    []string
+	}{
+		{
+			name:    "script tag",
+			source:  "hello <script>alert(1)</script>",
+			wantNot: []string{"<script", "alert(1)</script>"},
+			want:    []string{"hello"},
+		},
+		{
+			name:    "script block",
+			source:  "<script>\nalert(1)\n</script>",
+			wantNot: []string{"<script"},
+		},
+		{
+			name:    "javascript link",
+			source:  "[click](javascript:alert(1))",
+			wantNot: []string{"javascript:"},
+			want:    []string{"click"},
+		},
+		{
+			name:    "event handler attribute",
+			source:  "<img src=\"x\" onerror=\"alert(1)\">",
+			wantNot: []string{"onerror"},
+		},
+		{
+			name:   "safe markdown is kept",
+			source: "**bold** [link](https://example.com)",
+			want:   []string{"<strong>bold</strong>", `href="https://example.com"`, `rel="nofollow"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := HTML(tt.source)
+			for _, s := range tt.wantNot {
+				if strings.Contains(got, s) {
+					t.Errorf("HTML() = %q, must not contain %q", got, s)
+				}
+			}
+			for _, s := range tt.want {
+				if !strings.Contains(got, s) {
+					t.Errorf("HTML() = %q, want containing %q", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name      string
+		plainText string
+		maxRunes  int
+		want      string
+	}{
+		{"short", "hello world", 20, "hello world"},
+		{"whitespace is collapsed", "hello\n\n  world", 20, "hello world"},
+		{"exact length", "abcde", 5, "abcde"},
+		{"truncated", "abcdefgh", 5, "abcde…"},
+		{"truncated on rune boundary", "日本語の本文です", 3, "日本語…"},
+		{"trailing space trimmed", "hello world", 6, "hello…"},
+		{"emoji", "🍣🍺🍜🍙", 2, "🍣🍺…"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Excerpt(tt.plainText, tt.maxRunes); got != tt.want {
+				t.Errorf("Excerpt() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReadingMinutes(t *testing.T) {
+	tests := []struct {
+		name      string
+		plainText string
+		want      int
+	}{
+		{"empty", "", 1},
+		{"short english", "a quick read", 1},
+		{"english words", strings.Repeat("word ", 401), 3},
+		{"japanese chars", strings.Repeat("あ", 1000), 2},
+		{"japanese chars rounded up", strings.Repeat("漢", 1001), 3},
+		{"katakana", strings.Repeat("カ", 500), 1},
+		{"mixed", strings.Repeat("あ", 500) + " " + strings.Repeat("word ", 200), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReadingMinutes(tt.plainText); got != tt.want {
+				t.Errorf("ReadingMinutes() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Registry アプリケーションのメトリクスを登録するレジストリ
+// 各パッケージはpromauto.With(Registry)でメトリクスを定義する
+var Registry = prometheus.NewRegistry()
+
+// Factory Registryに登録するメトリクスを生成する
+var Factory = promauto.With(Registry)
+
+// LatencyBuckets レイテンシのヒストグラムのバケット(秒)
+var LatencyBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// Handler Prometheusのスクレイプ用のハンドラー(/metrics)
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
@@ -0,0 +1,81 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// 使われなくなったバケットを掃除する間隔
+const sweepInterval = time.Minute
+
+type bucket struct {
+	tokens   float64
+	last     time.Time
+	fullAt   time.Time // トークンが満タンに戻る時刻(これ以降は削除しても結果が変わらない)
+	capacity int
+}
+
+// MemoryStore プロセス内のメモリにトークンバケットを保持するストア
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{buckets: map[string]*bucket{}}
+}
+
+// Take: キーのバケットからトークンを1つ取得する
+func (s *MemoryStore) Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error) {
+	if limit.Unlimited() {
+		return Result{Allowed: true, Remaining: math.MaxInt32}, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok || b.capacity != limit.Burst {
+		b = &bucket{tokens: float64(limit.Burst), last: now, capacity: limit.Burst}
+		s.buckets[key] = b
+	}
+
+	// 経過時間に応じてトークンを補充
+	elapsed := now.Sub(b.last).Seconds()
+	if elapsed > 0 {
+		b.tokens = math.Min(float64(limit.Burst), b.tokens+elapsed*limit.Rate)
+		b.last = now
+	}
+
+	result := Result{}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration((1 - b.tokens) / limit.Rate * float64(time.Second))
+	}
+	result.Remaining = int(b.tokens)
+
+	missing := float64(limit.Burst) - b.tokens
+	b.fullAt = now.Add(time.Duration(missing / limit.Rate * float64(time.Second)))
+
+	return result, nil
+}
+
+// sweep: 満タンに戻ったバケットを削除する(呼び出し側でロックを取得していること)
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	for key, b := range s.buckets {
+		if now.After(b.fullAt) {
+			delete(s.buckets, key)
+		}
+	}
+	s.lastSweep = now
+}
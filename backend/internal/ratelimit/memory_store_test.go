@@ -0,0 +1,133 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// clock テスト用の時計 (Takeに渡す時刻を手動で進める)
+type clock struct {
+	now time.Time
+}
+
+func newClock() *clock {
+	return &clock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *clock) advance(d time.Duration) {
+	c.now = c.now.Add(d)
+}
+
+func take(t *testing.T, store *MemoryStore, key string, limit Limit, now time.Time) Result {
+	t.Helper()
+	result, err := store.Take(context.Background(), key, limit, now)
+	if err != nil {
+		t.Fatalf("Take() error = %v", err)
+	}
+	return result
+}
+
+func TestMemoryStoreTakeBurst(t *testing.T) {
+	store := NewMemoryStore()
+	clk := newClock()
+	limit := PerMinute(60, 3)
+
+	for i := range 3 {
+		result := take(t, store, "user:1", limit, clk.now)
+		if !result.Allowed {
+			t.Fatalf("request %d: Allowed = false, want true", i+1)
+		}
+		if want := 2 - i; result.Remaining != want {
+			t.Errorf("request %d: Remaining = %d, want %d", i+1, result.Remaining, want)
+		}
+	}
+
+	result := take(t, store, "user:1", limit, clk.now)
+	if result.Allowed {
+		t.Fatal("request over burst: Allowed = true, want false")
+	}
+	if result.RetryAfter != time.Second {
+		t.Errorf("RetryAfter = %s, want %s", result.RetryAfter, time.Second)
+	}
+
+	// 別のキーは影響を受けない
+	if result := take(t, store, "user:2", limit, clk.now); !result.Allowed {
+		t.Error("other key: Allowed = false, want true")
+	}
+}
+
+func TestMemoryStoreTakeRefill(t *testing.T) {
+	store := NewMemoryStore()
+	clk := newClock()
+	limit := PerMinute(30, 1) // 2秒に1トークン
+
+	if result := take(t, store, "k", limit, clk.now); !result.Allowed {
+		t.Fatal("first request: Allowed = false, want true")
+	}
+
+	clk.advance(500 * time.Millisecond)
+	result := take(t, store, "k", limit, clk.now)
+	if result.Allowed {
+		t.Fatal("before refill: Allowed = true, want false")
+	}
+	if want := 1500 * time.Millisecond; result.RetryAfter != want {
+		t.Errorf("RetryAfter = %s, want %s", result.RetryAfter, want)
+	}
+
+	clk.advance(result.RetryAfter)
+	if result := take(t, store, "k", limit, clk.now); !result.Allowed {
+		t.Fatal("after RetryAfter: Allowed = false, want true")
+	}
+
+	// 長時間経過してもバーストを超えて補充されない
+	clk.advance(time.Hour)
+	if result := take(t, store, "k", limit, clk.now); !result.Allowed || result.Remaining != 0 {
+		t.Errorf("after long idle: Allowed = %v, Remaining = %d, want true, 0", result.Allowed, result.Remaining)
+	}
+	if result := take(t, store, "k", limit, clk.now); result.Allowed {
+		t.Error("after long idle: second request Allowed = true, want false")
+	}
+}
+
+func TestMemoryStoreTakeUnlimited(t *testing.T) {
+	tests := []struct {
+		name  string
+		limit Limit
+	}{
+		{"zero value", Limit{}},
+		{"zero rate", PerMinute(0, 10)},
+		{"zero burst", PerMinute(60, 0)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := NewMemoryStore()
+			clk := newClock()
+			for i := range 100 {
+				if result := take(t, store, "k", tt.limit, clk.now); !result.Allowed {
+					t.Fatalf("request %d: Allowed = false, want true", i+1)
+				}
+			}
+			if len(store.buckets) != 0 {
+				t.Errorf("buckets = %d, want 0", len(store.buckets))
+			}
+		})
+	}
+}
+
+func TestMemoryStoreSweep(t *testing.T) {
+	store := NewMemoryStore()
+	clk := newClock()
+	limit := PerMinute(60, 2)
+
+	take(t, store, "idle", limit, clk.now)
+	clk.advance(sweepInterval + time.Second)
+	take(t, store, "active", limit, clk.now)
+
+	if _, ok := store.buckets["idle"]; ok {
+		t.Error("bucket refilled to capacity was not swept")
+	}
+	if _, ok := store.buckets["active"]; !ok {
+		t.Error("active bucket was swept")
+	}
+}
@@ -0,0 +1,35 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Limit トークンバケットの設定
+type Limit struct {
+	Rate  float64 // 1秒あたりに補充されるトークン数(0以下の場合は制限なし)
+	Burst int     // バケットの容量(連続して許可されるリクエスト数)
+}
+
+// PerMinute 1分あたりのリクエスト数でLimitを作成する
+func PerMinute(requests int, burst int) Limit {
+	return Limit{Rate: float64(requests) / 60, Burst: burst}
+}
+
+// Unlimited 制限なしの設定か
+func (l Limit) Unlimited() bool {
+	return l.Rate <= 0 || l.Burst <= 0
+}
+
+// Result トークン取得の結果
+type Result struct {
+	Allowed    bool
+	Remaining  int           // 残りのトークン数
+	RetryAfter time.Duration // 拒否された場合に、次のトークンが補充されるまでの時間
+}
+
+// Store キーごとのトークンバケットを保持するストア
+// 複数のサーバーで制限を共有する場合は、Redisなどの共有ストアを実装して差し替える
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit, now time.Time) (Result, error)
+}
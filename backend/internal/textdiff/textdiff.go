@@ -0,0 +1,168 @@
+package textdiff
+
+import (
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Mode 差分を取る単位
+type Mode string
+
+const (
+	ModeLine Mode = "line"
+	ModeWord Mode = "word"
+)
+
+// Operation 差分の種類
+type Operation string
+
+const (
+	OperationEqual  Operation = "equal"
+	OperationInsert Operation = "insert"
+	OperationDelete Operation = "delete"
+)
+
+// Chunk 差分の1区間 (すべてのChunkのTextを順に連結すると、Deleteを除けば変更後、Insertを除けば変更前の文字列になる)
+type Chunk struct {
+	Operation Operation
+	Text      string
+}
+
+// 差分の計算を打ち切るまでの時間
+// 長い本文で計算に時間がかかる場合は、最小ではないが正しい差分を返す
+const diffTimeout = time.Second
+
+// Diff 2つの文字列の差分を行または単語の単位で計算する
+func Diff(from, to string, mode Mode) []Chunk {
+	split := splitLines
+	if mode == ModeWord {
+		split = splitWords
+	}
+
+	// トークンを1文字に置き換えて文字単位の差分を計算し、元のトークンに戻す
+	t := &tokenizer{index: map[string]rune{}}
+	fromRunes := t.encode(split(from))
+	toRunes := t.encode(split(to))
+
+	dmp := diffmatchpatch.New()
+	dmp.DiffTimeout = diffTimeout
+	diffs := dmp.DiffMainRunes(fromRunes, toRunes, false)
+
+	chunks := make([]Chunk, 0, len(diffs))
+	for _, d := range diffs {
+		var op Operation
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			op = OperationInsert
+		case diffmatchpatch.DiffDelete:
+			op = OperationDelete
+		default:
+			op = OperationEqual
+		}
+		chunks = append(chunks, Chunk{Operation: op, Text: t.decode(d.Text)})
+	}
+	return chunks
+}
+
+// tokenizer トークンと置き換える文字の対応
+type tokenizer struct {
+	tokens []string
+	index  map[string]rune
+}
+
+func (t *tokenizer) encode(tokens []string) []rune {
+	runes := make([]rune, 0, len(tokens))
+	for _, token := range tokens {
+		r, ok := t.index[token]
+		if !ok {
+			r = tokenRune(len(t.tokens))
+			t.tokens = append(t.tokens, token)
+			t.index[token] = r
+		}
+		runes = append(runes, r)
+	}
+	return runes
+}
+
+func (t *tokenizer) decode(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		b.WriteString(t.tokens[tokenIndex(r)])
+	}
+	return b.String()
+}
+
+// サロゲート領域の文字は文字列に変換すると失われるため使わない
+const surrogateMin, surrogateMax = 0xD800, 0xDFFF
+
+func tokenRune(i int) rune {
+	r := rune(i + 1) // NUL文字は使わない
+	if r >= surrogateMin {
+		r += surrogateMax - surrogateMin + 1
+	}
+	return r
+}
+
+func tokenIndex(r rune) int {
+	if r > surrogateMax {
+		r -= surrogateMax - surrogateMin + 1
+	}
+	return int(r) - 1
+}
+
+// splitLines 改行を含めて1行ずつに分ける
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// splitWords 英数字の連続・空白の連続をそれぞれ1語とし、日本語や記号は1文字ずつに分ける
+func splitWords(s string) []string {
+	var words []string
+	start := 0
+	for start < len(s) {
+		r, size := utf8.DecodeRuneInString(s[start:])
+		end := start + size
+		if kind := wordKind(r); kind != kindSingle {
+			for end < len(s) {
+				next, nextSize := utf8.DecodeRuneInString(s[end:])
+				if wordKind(next) != kind {
+					break
+				}
+				end += nextSize
+			}
+		}
+		words = append(words, s[start:end])
+		start = end
+	}
+	return words
+}
+
+const (
+	kindSingle = iota
+	kindSpace
+	kindWord
+)
+
+func wordKind(r rune) int {
+	switch {
+	case unicode.IsSpace(r):
+		return kindSpace
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana):
+		return kindSingle
+	case unicode.IsLetter(r), unicode.IsDigit(r), r == '_':
+		return kindWord
+	default:
+		return kindSingle
+	}
+}
@@ -0,0 +1,142 @@
+package textdiff
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		mode     Mode
+		want     []Chunk
+	}{
+		{
+			name: "equal",
+			from: "a\nb\n",
+			to:   "a\nb\n",
+			mode: ModeLine,
+			want: []Chunk{{OperationEqual, "a\nb\n"}},
+		},
+		{
+			name: "both empty",
+			from: "",
+			to:   "",
+			mode: ModeLine,
+			want: []Chunk{},
+		},
+		{
+			name: "insert only",
+			from: "a\nc\n",
+			to:   "a\nb\nc\n",
+			mode: ModeLine,
+			want: []Chunk{
+				{OperationEqual, "a\n"},
+				{OperationInsert, "b\n"},
+				{OperationEqual, "c\n"},
+			},
+		},
+		{
+			name: "delete only",
+			from: "a\nb\nc\n",
+			to:   "a\nc\n",
+			mode: ModeLine,
+			want: []Chunk{
+				{OperationEqual, "a\n"},
+				{OperationDelete, "b\n"},
+				{OperationEqual, "c\n"},
+			},
+		},
+		{
+			name: "mixed",
+			from: "title\nold line\nfooter",
+			to:   "title\nnew line\nfooter\nappendix\n",
+			mode: ModeLine,
+			want: []Chunk{
+				{OperationEqual, "title\n"},
+				{OperationDelete, "old line\nfooter"},
+				{OperationInsert, "new line\nfooter\nappendix\n"},
+			},
+		},
+		{
+			name: "word",
+			from: "hello old world",
+			to:   "hello new world",
+			mode: ModeWord,
+			want: []Chunk{
+				{OperationEqual, "hello "},
+				{OperationDelete, "old"},
+				{OperationInsert, "new"},
+				{OperationEqual, " world"},
+			},
+		},
+		{
+			name: "CJK word",
+			from: "今日は晴れです",
+			to:   "今日は雨です",
+			mode: ModeWord,
+			want: []Chunk{
+				{OperationEqual, "今日は"},
+				{OperationDelete, "晴れ"},
+				{OperationInsert, "雨"},
+				{OperationEqual, "です"},
+			},
+		},
+		{
+			name: "CJK mixed with latin",
+			from: "Go言語で書く",
+			to:   "Rust言語で書く",
+			mode: ModeWord,
+			want: []Chunk{
+				{OperationDelete, "Go"},
+				{OperationInsert, "Rust"},
+				{OperationEqual, "言語で書く"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.from, tt.to, tt.mode)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Diff() = %q, want %q", got, tt.want)
+			}
+			assertReconstructs(t, got, tt.from, tt.to)
+		})
+	}
+}
+
+func TestDiffManyTokens(t *testing.T) {
+	// サロゲート領域を超える数のトークンでも元の文字列に戻せること
+	var from, to strings.Builder
+	for i := range 70000 {
+		line := fmt.Sprintf("line %d\n", i)
+		from.WriteString(line)
+		if i != 60000 {
+			to.WriteString(line)
+		}
+	}
+	assertReconstructs(t, Diff(from.String(), to.String(), ModeLine), from.String(), to.String())
+}
+
+// assertReconstructs 差分から変更前後の文字列を組み立て直せるか確認する
+func assertReconstructs(t *testing.T, chunks []Chunk, from, to string) {
+	t.Helper()
+	var before, after strings.Builder
+	for _, c := range chunks {
+		if c.Operation != OperationInsert {
+			before.WriteString(c.Text)
+		}
+		if c.Operation != OperationDelete {
+			after.WriteString(c.Text)
+		}
+	}
+	if before.String() != from {
+		t.Errorf("reconstructed from = %q, want %q", before.String(), from)
+	}
+	if after.String() != to {
+		t.Errorf("reconstructed to = %q, want %q", after.String(), to)
+	}
+}
@@ -0,0 +1,192 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/domain/repository"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	apiKeyTokenPrefix     = "esk"       // APIキーの先頭に付ける識別用の文字列
+	apiKeyTouchInterval   = time.Minute // 最終利用日時を更新する最短間隔
+	apiKeyPrefixBytes     = 6
+	apiKeySecretBytes     = 32
+	apiKeyTokenPartsCount = 3
+)
+
+var validAPIKeyScopes = []string{
+	model.ScopeArticlesRead,
+	model.ScopeArticlesWrite,
+	model.ScopeSearchAdmin,
+}
+
+type CreateAPIKeyInput struct {
+	UserID    uint
+	Name      string
+	Scopes    []string
+	ExpiresAt *time.Time
+}
+
+type APIKeyUsecase interface {
+	ListAPIKeys(ctx context.Context) ([]*model.APIKey, error)
+
+	// 作成したAPIキーと、平文のキー(この時にしか取得できない)を返す
+	CreateAPIKey(ctx context.Context, input CreateAPIKeyInput) (*model.APIKey, string, error)
+	RevokeAPIKey(ctx context.Context, id uint) (*model.APIKey, error)
+
+	// 平文のキーを検証し、有効なAPIキー(所有者を含む)を返す
+	Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error)
+}
+
+type apiKeyUsecase struct {
+	dbRepo repository.APIKeyRepository
+}
+
+func NewAPIKeyUsecase(dbRepo repository.APIKeyRepository) APIKeyUsecase {
+	return &apiKeyUsecase{
+		dbRepo: dbRepo,
+	}
+}
+
+// ListAPIKeys: APIキー一覧取得
+func (u *apiKeyUsecase) ListAPIKeys(ctx context.Context) ([]*model.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.ListAPIKeys")
+	defer span.End()
+
+	apiKeys, err := u.dbRepo.ListAPIKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return apiKeys, nil
+}
+
+// CreateAPIKey: APIキー発行
+func (u *apiKeyUsecase) CreateAPIKey(ctx context.Context, input CreateAPIKeyInput) (*model.APIKey, string, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.CreateAPIKey")
+	defer span.End()
+
+	if strings.TrimSpace(input.Name) == "" {
+		return nil, "", apperror.Validation("name is required")
+	}
+	if len(input.Scopes) == 0 {
+		return nil, "", apperror.Validation("at least one scope is required")
+	}
+	for _, scope := range input.Scopes {
+		if !slices.Contains(validAPIKeyScopes, scope) {
+			return nil, "", apperror.Validation(fmt.Sprintf("unknown scope: %s", scope))
+		}
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(time.Now()) {
+		return nil, "", apperror.Validation("expiresAt must be in the future")
+	}
+
+	// 識別子は区切り文字("_")を含まないよう16進数で表す
+	prefixBytes, err := randomBytes(apiKeyPrefixBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	secretBytes, err := randomBytes(apiKeySecretBytes)
+	if err != nil {
+		return nil, "", err
+	}
+	prefix := hex.EncodeToString(prefixBytes)
+	secret := base64.RawURLEncoding.EncodeToString(secretBytes)
+
+	apiKey := &model.APIKey{
+		UserID:     input.UserID,
+		Name:       input.Name,
+		Prefix:     prefix,
+		SecretHash: hashSecret(secret),
+		Scopes:     slices.Compact(slices.Sorted(slices.Values(input.Scopes))),
+		ExpiresAt:  input.ExpiresAt,
+	}
+	if err := u.dbRepo.CreateAPIKey(ctx, apiKey); err != nil {
+		return nil, "", err
+	}
+
+	rawKey := strings.Join([]string{apiKeyTokenPrefix, prefix, secret}, "_")
+	return apiKey, rawKey, nil
+}
+
+// RevokeAPIKey: APIキー失効
+func (u *apiKeyUsecase) RevokeAPIKey(ctx context.Context, id uint) (*model.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.RevokeAPIKey")
+	defer span.End()
+
+	apiKey, err := u.dbRepo.GetAPIKeyByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if apiKey.RevokedAt != nil {
+		return apiKey, nil
+	}
+
+	now := time.Now()
+	apiKey.RevokedAt = &now
+	if err := u.dbRepo.UpdateAPIKey(ctx, apiKey); err != nil {
+		return nil, err
+	}
+	return apiKey, nil
+}
+
+// Authenticate: APIキー認証
+func (u *apiKeyUsecase) Authenticate(ctx context.Context, rawKey string) (*model.APIKey, error) {
+	ctx, span := tracer.Start(ctx, "APIKeyUsecase.Authenticate")
+	defer span.End()
+
+	invalid := apperror.Unauthenticated("invalid api key")
+
+	// "esk_<prefix>_<secret>" の形式を分解
+	parts := strings.SplitN(rawKey, "_", apiKeyTokenPartsCount)
+	if len(parts) != apiKeyTokenPartsCount || parts[0] != apiKeyTokenPrefix {
+		return nil, invalid
+	}
+
+	apiKey, err := u.dbRepo.GetAPIKeyByPrefix(ctx, parts[1])
+	if err != nil {
+		if apperror.Is(err, apperror.CodeNotFound) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if subtle.ConstantTimeCompare([]byte(apiKey.SecretHash), []byte(hashSecret(parts[2]))) != 1 || !apiKey.IsActive(now) {
+		return nil, invalid
+	}
+
+	// 最終利用日時の更新はリクエストごとに行わず、一定間隔に間引く
+	if apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) > apiKeyTouchInterval {
+		if err := u.dbRepo.TouchAPIKey(ctx, apiKey.ID, now); err != nil {
+			logger.WarnContext(ctx, "⚠️ APIキーの最終利用日時の更新に失敗しました", "error", err)
+		}
+		apiKey.LastUsedAt = &now
+	}
+
+	return apiKey, nil
+}
+
+// randomBytes: 暗号論的乱数を生成
+func randomBytes(size int) ([]byte, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// hashSecret: シークレットのハッシュ値(十分なエントロピーがあるためソルトなしのSHA-256で保存)
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
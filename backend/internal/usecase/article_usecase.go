@@ -2,15 +2,42 @@ package usecase
 
 import (
 	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/domain/event"
 	"elasticsearch-sample/backend/internal/domain/model"
 	"elasticsearch-sample/backend/internal/domain/repository"
+	"elasticsearch-sample/backend/internal/textdiff"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+const (
+	scheduledPublishBatchSize = 100             // 予約公開ワーカーが1回の実行で処理する記事数の上限
+	scheduledPublishLease     = 5 * time.Minute // 処理中の記事を他のワーカーが処理し直すまでの時間
+)
+
+const (
+	maxTagsPerArticle = 10 // 1つの記事に付けられるタグ数の上限
+	maxTagLength      = 32 // タグ名の文字数の上限
+	maxPopularTags    = 100
+)
+
+// 記事の履歴を一度に取得できる件数の上限
+const maxRevisionsPerPage = 100
+
+// ゴミ箱を空にするワーカーが1回の実行で処理する記事数の上限
+const trashPurgeBatchSize = 100
+
 type CreateArticleInput struct {
 	Title   string
 	Content string
 	Status  string
 	UserID  uint
+	Tags    []string
 }
 
 type UpdateArticleInput struct {
@@ -18,16 +45,63 @@ type UpdateArticleInput struct {
 	Title     *string
 	Content   *string
 	Status    *string
+	Tags      *[]string // nilの場合は変更しない
+	EditorID  uint      // 0の場合はシステムによる更新(予約公開など)
+}
+
+type ListUserArticlesInput struct {
+	UserID   uint
+	Statuses []string // 空の場合は全てのステータス
+	AfterID  uint     // 0の場合は先頭から
+	Limit    int
+}
+
+type ListDeletedArticlesInput struct {
+	UserID  uint
+	AfterID uint // 0の場合は先頭から
+	Limit   int
+}
+
+type ListArticleRevisionsInput struct {
+	ArticleID     uint
+	BeforeVersion int // 0の場合は最新から
+	Limit         int
+}
+
+// RevisionDiff 2つの履歴の間のタイトルと本文の差分
+type RevisionDiff struct {
+	From    *model.ArticleRevision
+	To      *model.ArticleRevision
+	Title   []textdiff.Chunk
+	Content []textdiff.Chunk
 }
 
 type ArticleUsecase interface {
 	GetArticleByID(ctx context.Context, articleID uint) (*model.Article, error)
-	ListArticles(ctx context.Context, page int, pageSize int) ([]*model.Article, error)
-	SearchArticles(ctx context.Context, keyword string) ([]*model.Article, error)
+	// 過去のスラッグでも取得できる(呼び出し元は記事の現在のスラッグと比べてリダイレクトする)
+	GetArticleBySlug(ctx context.Context, userID uint, slug string) (*model.Article, error)
+	// 公開中の記事と、viewerIDのユーザーの記事を取得する(未ログインの場合は0)
+	ListArticles(ctx context.Context, viewerID uint, page int, pageSize int) ([]*model.Article, error)
+	ListUserArticles(ctx context.Context, input ListUserArticlesInput) ([]*model.Article, int64, error)
+	SearchArticles(ctx context.Context, keyword string, tags []string) ([]*model.Article, error)
+	PopularTags(ctx context.Context, limit int) ([]model.TagCount, error)
 
 	CreateArticle(ctx context.Context, input CreateArticleInput) (*model.Article, error)
 	UpdateArticle(ctx context.Context, input UpdateArticleInput) (*model.Article, error)
 	DeleteArticle(ctx context.Context, articleID uint) error
+	ScheduleArticlePublication(ctx context.Context, articleID uint, userID uint, publishAt time.Time) (*model.Article, error)
+	PublishScheduledArticles(ctx context.Context, now time.Time) (int, error)
+
+	GetDeletedArticleByID(ctx context.Context, articleID uint) (*model.Article, error)
+	ListDeletedArticles(ctx context.Context, input ListDeletedArticlesInput) ([]*model.Article, int64, error)
+	RestoreArticle(ctx context.Context, articleID uint) (*model.Article, error)
+	PurgeArticle(ctx context.Context, articleID uint) error
+	// deletedBeforeより前に削除された記事を完全に削除し、削除した件数を返す
+	PurgeDeletedArticles(ctx context.Context, deletedBefore time.Time) (int, error)
+
+	ListRevisions(ctx context.Context, input ListArticleRevisionsInput) ([]*model.ArticleRevision, int64, error)
+	DiffRevisions(ctx context.Context, articleID uint, from int, to int, mode textdiff.Mode) (*RevisionDiff, error)
+	RestoreRevision(ctx context.Context, articleID uint, version int, editorID uint) (*model.Article, error)
 
 	ReindexSearchEngine() error
 
@@ -35,19 +109,31 @@ type ArticleUsecase interface {
 }
 
 type articleUsecase struct {
-	dbRepo     repository.ArticleRepository
-	searchRepo repository.ArticleSearchRepository
+	tx           repository.Transactor // 記事と履歴の保存を1つのトランザクションにまとめる
+	dbRepo       repository.ArticleRepository
+	revisionRepo repository.ArticleRevisionRepository
+	searchRepo   repository.ArticleSearchRepository
+	attachments  AttachmentUsecase // 記事を完全に削除する際に添付ファイルの本体を削除する
+	publisher    event.ArticleEventPublisher
 }
 
-func NewArticleUsecase(dbRepo repository.ArticleRepository, searchRepo repository.ArticleSearchRepository) ArticleUsecase {
+// publisherがnilの場合、記事イベントは配信されない
+func NewArticleUsecase(tx repository.Transactor, dbRepo repository.ArticleRepository, revisionRepo repository.ArticleRevisionRepository, searchRepo repository.ArticleSearchRepository, attachments AttachmentUsecase, publisher event.ArticleEventPublisher) ArticleUsecase {
 	return &articleUsecase{
-		dbRepo:     dbRepo,
-		searchRepo: searchRepo,
+		tx:           tx,
+		dbRepo:       dbRepo,
+		revisionRepo: revisionRepo,
+		searchRepo:   searchRepo,
+		attachments:  attachments,
+		publisher:    publisher,
 	}
 }
 
 // GetArticleByID: IDで記事取得
 func (u *articleUsecase) GetArticleByID(ctx context.Context, articleID uint) (*model.Article, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.GetArticleByID")
+	defer span.End()
+
 	article, err := u.dbRepo.GetArticleByID(ctx, int64(articleID))
 	if err != nil {
 		return nil, err
@@ -55,49 +141,132 @@ func (u *articleUsecase) GetArticleByID(ctx context.Context, articleID uint) (*m
 	return article, nil
 }
 
+// GetArticleBySlug: 作成者とスラッグで記事取得
+func (u *articleUsecase) GetArticleBySlug(ctx context.Context, userID uint, slug string) (*model.Article, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.GetArticleBySlug")
+	defer span.End()
+
+	article, err := u.dbRepo.GetArticleBySlug(ctx, userID, slug)
+	if err != nil {
+		return nil, err
+	}
+	return article, nil
+}
+
 // ListArticles: 記事一覧取得
-func (u *articleUsecase) ListArticles(ctx context.Context, page int, pageSize int) ([]*model.Article, error) {
-	articles, err := u.dbRepo.ListArticles(ctx, page, pageSize)
+func (u *articleUsecase) ListArticles(ctx context.Context, viewerID uint, page int, pageSize int) ([]*model.Article, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.ListArticles")
+	defer span.End()
+
+	articles, err := u.dbRepo.ListVisibleArticles(ctx, viewerID, page, pageSize)
 	if err != nil {
 		return nil, err
 	}
 	return articles, nil
 }
 
-// SearchArticles: キーワードで記事検索
-func (u *articleUsecase) SearchArticles(ctx context.Context, keyword string) ([]*model.Article, error) {
-	articles, err := u.searchRepo.SimpleSearch(keyword)
+// ListUserArticles: ユーザーの記事一覧と総件数を取得
+func (u *articleUsecase) ListUserArticles(ctx context.Context, input ListUserArticlesInput) ([]*model.Article, int64, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.ListUserArticles")
+	defer span.End()
+
+	articles, err := u.dbRepo.ListArticlesByUser(ctx, input.UserID, input.Statuses, input.AfterID, input.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	totalCount, err := u.dbRepo.CountArticlesByUser(ctx, input.UserID, input.Statuses)
+	if err != nil {
+		return nil, 0, err
+	}
+	return articles, totalCount, nil
+}
+
+// SearchArticles: キーワードとタグで記事検索
+func (u *articleUsecase) SearchArticles(ctx context.Context, keyword string, tags []string) ([]*model.Article, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.SearchArticles")
+	defer span.End()
+
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := u.searchRepo.SimpleSearch(ctx, repository.ArticleSearchQuery{
+		Keyword: keyword,
+		Tags:    tags,
+	})
 	if err != nil {
 		return nil, err
 	}
 	return articles, nil
 }
 
+// PopularTags: 公開記事に多く付けられているタグを取得
+func (u *articleUsecase) PopularTags(ctx context.Context, limit int) ([]model.TagCount, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.PopularTags")
+	defer span.End()
+
+	if limit < 1 || limit > maxPopularTags {
+		return nil, apperror.Validation(fmt.Sprintf("limit must be between 1 and %d", maxPopularTags))
+	}
+	return u.searchRepo.PopularTags(ctx, limit)
+}
+
 // CreateArticle: 記事作成
 func (u *articleUsecase) CreateArticle(ctx context.Context, input CreateArticleInput) (*model.Article, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.CreateArticle")
+	defer span.End()
+
+	tagNames, err := normalizeTags(input.Tags)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := u.dbRepo.FindOrCreateTags(ctx, tagNames)
+	if err != nil {
+		return nil, err
+	}
+	slug, err := u.newSlug(ctx, input.UserID, 0, input.Title)
+	if err != nil {
+		return nil, err
+	}
+
 	// DBに記事作成
 	article := &model.Article{
 		Title:   input.Title,
+		Slug:    slug,
 		Content: input.Content,
 		Status:  input.Status,
 		UserID:  input.UserID,
+		Tags:    tags,
 	}
-	createdArticle, err := u.dbRepo.CreateArticle(ctx, article)
+	// 記事と最初の履歴をまとめて保存する
+	var createdArticle *model.Article
+	err = u.tx.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if createdArticle, err = u.dbRepo.CreateArticle(ctx, article); err != nil {
+			return err
+		}
+		return u.recordRevision(ctx, createdArticle, input.UserID)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// 検索エンジンにインデックス登録
-	err = u.searchRepo.Index(nil, createdArticle)
+	// 検索エンジンにインデックス登録(コミット後に行う)
+	err = u.searchRepo.Index(ctx, nil, createdArticle)
 	if err != nil {
 		return nil, err
 	}
 
+	u.publishEvent(ctx, event.ArticleCreated, createdArticle)
+
 	return createdArticle, nil
 }
 
 // UpdateArticle: 記事更新
 func (u *articleUsecase) UpdateArticle(ctx context.Context, input UpdateArticleInput) (*model.Article, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.UpdateArticle")
+	defer span.End()
+
 	article, err := u.dbRepo.GetArticleByID(ctx, int64(input.ArticleID))
 	if err != nil {
 		return nil, err
@@ -107,6 +276,10 @@ func (u *articleUsecase) UpdateArticle(ctx context.Context, input UpdateArticleI
 	hasChanged := false
 	if input.Title != nil {
 		article.Title = *input.Title
+		// タイトルに合わせてスラッグも変更する(古いスラッグはリダイレクト用に残る)
+		if article.Slug, err = u.newSlug(ctx, article.UserID, article.ID, article.Title); err != nil {
+			return nil, err
+		}
 		hasChanged = true
 	}
 	if input.Content != nil {
@@ -115,28 +288,69 @@ func (u *articleUsecase) UpdateArticle(ctx context.Context, input UpdateArticleI
 	}
 	if input.Status != nil {
 		article.Status = *input.Status
+		// ステータスが変わった場合は予約公開を解除
+		article.PublishAt = nil
+		hasChanged = true
+	}
+
+	var tags []model.Tag
+	if input.Tags != nil {
+		tagNames, err := normalizeTags(*input.Tags)
+		if err != nil {
+			return nil, err
+		}
+		if tags, err = u.dbRepo.FindOrCreateTags(ctx, tagNames); err != nil {
+			return nil, err
+		}
 		hasChanged = true
 	}
 
 	if !hasChanged {
 		return article, nil
 	}
-	updatedArticle, err := u.dbRepo.UpdateArticle(ctx, article)
+	// 履歴のない変更が残らないよう、記事・タグ・履歴をまとめて保存する
+	var updatedArticle *model.Article
+	err = u.tx.Transaction(ctx, func(ctx context.Context) error {
+		var err error
+		if updatedArticle, err = u.dbRepo.UpdateArticle(ctx, article); err != nil {
+			return err
+		}
+		if input.Tags != nil {
+			if err := u.dbRepo.ReplaceArticleTags(ctx, updatedArticle, tags); err != nil {
+				return err
+			}
+		}
+		return u.recordRevision(ctx, updatedArticle, input.EditorID)
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	// 検索エンジンで記事更新
-	err = u.searchRepo.Index(nil, updatedArticle)
+	// 検索エンジンで記事更新(コミット後に行う)
+	err = u.searchRepo.Index(ctx, nil, updatedArticle)
 	if err != nil {
 		return nil, err
 	}
 
+	eventType := event.ArticleUpdated
+	if input.Status != nil {
+		switch *input.Status {
+		case "published":
+			eventType = event.ArticlePublished
+		case "archived":
+			eventType = event.ArticleArchived
+		}
+	}
+	u.publishEvent(ctx, eventType, updatedArticle)
+
 	return updatedArticle, nil
 }
 
-// DeleteArticle: 記事削除
+// DeleteArticle: 記事をゴミ箱に移動(論理削除し、検索対象から外す)
 func (u *articleUsecase) DeleteArticle(ctx context.Context, articleID uint) error {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.DeleteArticle")
+	defer span.End()
+
 	id := int64(articleID)
 
 	// DBから記事削除
@@ -146,14 +360,332 @@ func (u *articleUsecase) DeleteArticle(ctx context.Context, articleID uint) erro
 	}
 
 	// 検索エンジンから記事削除
-	err = u.searchRepo.Delete(id)
+	err = u.searchRepo.Delete(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ScheduleArticlePublication: 記事の予約公開日時を設定(作成者のみ)
+func (u *articleUsecase) ScheduleArticlePublication(ctx context.Context, articleID uint, userID uint, publishAt time.Time) (*model.Article, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.ScheduleArticlePublication")
+	defer span.End()
+
+	article, err := u.dbRepo.GetArticleByID(ctx, int64(articleID))
+	if err != nil {
+		return nil, err
+	}
+	if article.UserID != userID {
+		return nil, apperror.Forbidden("only the author can schedule this article")
+	}
+
+	if article.Status != "draft" {
+		return nil, apperror.Validation("only draft articles can be scheduled")
+	}
+	if !publishAt.After(time.Now()) {
+		return nil, apperror.Validation("publishAt must be in the future")
+	}
+
+	// DBに予約日時を保存
+	article.PublishAt = &publishAt
+	updatedArticle, err := u.dbRepo.UpdateArticle(ctx, article)
+	if err != nil {
+		return nil, err
+	}
+	// 以前の予約で確保されていた場合は、新しい日時に公開されるよう確保を解除する
+	if err := u.dbRepo.ReleaseScheduledArticle(ctx, article.ID); err != nil {
+		return nil, err
+	}
+
+	// 検索エンジンで記事更新
+	err = u.searchRepo.Index(ctx, nil, updatedArticle)
+	if err != nil {
+		return nil, err
+	}
+
+	u.publishEvent(ctx, event.ArticleUpdated, updatedArticle)
+
+	return updatedArticle, nil
+}
+
+// PublishScheduledArticles: 公開予定日時を過ぎた記事を公開し、公開した件数を返す
+func (u *articleUsecase) PublishScheduledArticles(ctx context.Context, now time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.PublishScheduledArticles")
+	defer span.End()
+
+	// 他のサーバーと重複しないよう、対象記事を確保(予約は公開と同時に解除される)
+	articles, err := u.dbRepo.ClaimDueScheduledArticles(ctx, now, scheduledPublishLease, scheduledPublishBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	published := 0
+	var errs []error
+	for _, article := range articles {
+		ok, err := u.publishScheduledArticle(ctx, article, now)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to publish article %d: %w", article.ID, err))
+			// 次回の実行で再試行できるよう確保を解除する(解除に失敗してもleaseを過ぎれば再試行される)
+			if err := u.dbRepo.ReleaseScheduledArticle(ctx, article.ID); err != nil {
+				errs = append(errs, fmt.Errorf("failed to release scheduled article %d: %w", article.ID, err))
+			}
+			continue
+		}
+		if ok {
+			published++
+		}
+	}
+
+	return published, errors.Join(errs...)
+}
+
+// publishScheduledArticle: 確保した記事を公開し、履歴・検索エンジン・イベントに反映する
+// 確保した後に記事が変更された場合は公開せずにfalseを返す
+func (u *articleUsecase) publishScheduledArticle(ctx context.Context, article *model.Article, now time.Time) (bool, error) {
+	var publishedArticle *model.Article
+	err := u.tx.Transaction(ctx, func(ctx context.Context) error {
+		ok, err := u.dbRepo.PublishScheduledArticle(ctx, article, now)
+		if err != nil || !ok {
+			return err
+		}
+		if publishedArticle, err = u.dbRepo.GetArticleByID(ctx, int64(article.ID)); err != nil {
+			return err
+		}
+		return u.recordRevision(ctx, publishedArticle, 0)
+	})
+	if err != nil || publishedArticle == nil {
+		return false, err
+	}
+
+	if err := u.searchRepo.Index(ctx, nil, publishedArticle); err != nil {
+		return false, err
+	}
+	u.publishEvent(ctx, event.ArticlePublished, publishedArticle)
+	return true, nil
+}
+
+// GetDeletedArticleByID: IDでゴミ箱の記事取得
+func (u *articleUsecase) GetDeletedArticleByID(ctx context.Context, articleID uint) (*model.Article, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.GetDeletedArticleByID")
+	defer span.End()
+
+	return u.dbRepo.GetDeletedArticleByID(ctx, int64(articleID))
+}
+
+// ListDeletedArticles: ユーザーのゴミ箱の記事一覧と総件数を取得
+func (u *articleUsecase) ListDeletedArticles(ctx context.Context, input ListDeletedArticlesInput) ([]*model.Article, int64, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.ListDeletedArticles")
+	defer span.End()
+
+	articles, err := u.dbRepo.ListDeletedArticlesByUser(ctx, input.UserID, input.AfterID, input.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	totalCount, err := u.dbRepo.CountDeletedArticlesByUser(ctx, input.UserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return articles, totalCount, nil
+}
+
+// RestoreArticle: ゴミ箱の記事を元に戻し、検索エンジンに再登録
+func (u *articleUsecase) RestoreArticle(ctx context.Context, articleID uint) (*model.Article, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.RestoreArticle")
+	defer span.End()
+
+	id := int64(articleID)
+	if err := u.dbRepo.RestoreArticle(ctx, id); err != nil {
+		return nil, err
+	}
+	article, err := u.dbRepo.GetArticleByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// 検索エンジンに記事登録
+	if err := u.searchRepo.Index(ctx, nil, article); err != nil {
+		return nil, err
+	}
+
+	return article, nil
+}
+
+// PurgeArticle: ゴミ箱の記事を完全に削除(添付ファイルの本体も削除する)
+func (u *articleUsecase) PurgeArticle(ctx context.Context, articleID uint) error {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.PurgeArticle")
+	defer span.End()
+
+	// 行は外部キーの制約で削除されるため、削除前に添付ファイルを取得しておく
+	attachments, err := u.attachments.ListAttachments(ctx, articleID)
 	if err != nil {
 		return err
 	}
 
+	id := int64(articleID)
+	if err := u.dbRepo.PurgeArticle(ctx, id); err != nil {
+		return err
+	}
+
+	// ゴミ箱に移動した時点で削除済みだが、失敗していた場合に備えて検索エンジンからも削除する
+	if err := u.searchRepo.Delete(ctx, id); err != nil {
+		return err
+	}
+	u.attachments.DeleteAttachmentFiles(ctx, attachments)
+
+	logger.InfoContext(ctx, "🗑️ 記事を完全に削除しました", "article_id", articleID)
 	return nil
 }
 
+// PurgeDeletedArticles: 保持期間を過ぎたゴミ箱の記事を完全に削除し、削除した件数を返す
+func (u *articleUsecase) PurgeDeletedArticles(ctx context.Context, deletedBefore time.Time) (int, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.PurgeDeletedArticles")
+	defer span.End()
+
+	articles, err := u.dbRepo.ListArticlesDeletedBefore(ctx, deletedBefore, trashPurgeBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	purged := 0
+	var errs []error
+	for _, article := range articles {
+		if err := u.PurgeArticle(ctx, article.ID); err != nil {
+			// 他のサーバーが先に削除した場合はエラーにしない
+			if !apperror.Is(err, apperror.CodeNotFound) {
+				errs = append(errs, fmt.Errorf("failed to purge article %d: %w", article.ID, err))
+			}
+			continue
+		}
+		purged++
+	}
+
+	return purged, errors.Join(errs...)
+}
+
+// ListRevisions: 記事の更新履歴を新しい順に取得し、総件数と合わせて返す
+func (u *articleUsecase) ListRevisions(ctx context.Context, input ListArticleRevisionsInput) ([]*model.ArticleRevision, int64, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.ListRevisions")
+	defer span.End()
+
+	if input.Limit < 1 || input.Limit > maxRevisionsPerPage {
+		return nil, 0, apperror.Validation(fmt.Sprintf("limit must be between 1 and %d", maxRevisionsPerPage))
+	}
+	revisions, err := u.revisionRepo.ListRevisions(ctx, input.ArticleID, input.BeforeVersion, input.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	totalCount, err := u.revisionRepo.CountRevisions(ctx, input.ArticleID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return revisions, totalCount, nil
+}
+
+// DiffRevisions: 2つの履歴のタイトルと本文の差分を計算
+func (u *articleUsecase) DiffRevisions(ctx context.Context, articleID uint, from int, to int, mode textdiff.Mode) (*RevisionDiff, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.DiffRevisions")
+	defer span.End()
+
+	if mode != textdiff.ModeLine && mode != textdiff.ModeWord {
+		return nil, apperror.Validation(fmt.Sprintf("unknown diff mode: %q", mode))
+	}
+	fromRevision, err := u.revisionRepo.GetRevision(ctx, articleID, from)
+	if err != nil {
+		return nil, err
+	}
+	toRevision, err := u.revisionRepo.GetRevision(ctx, articleID, to)
+	if err != nil {
+		return nil, err
+	}
+
+	return &RevisionDiff{
+		From:    fromRevision,
+		To:      toRevision,
+		Title:   textdiff.Diff(fromRevision.Title, toRevision.Title, textdiff.ModeWord),
+		Content: textdiff.Diff(fromRevision.Content, toRevision.Content, mode),
+	}, nil
+}
+
+// RestoreRevision: 記事のタイトルと本文を指定した履歴の内容に戻す
+// 履歴は書き換えず、戻した内容を新しい履歴として記録する(ステータスは変更しない)
+func (u *articleUsecase) RestoreRevision(ctx context.Context, articleID uint, version int, editorID uint) (*model.Article, error) {
+	ctx, span := tracer.Start(ctx, "ArticleUsecase.RestoreRevision")
+	defer span.End()
+
+	revision, err := u.revisionRepo.GetRevision(ctx, articleID, version)
+	if err != nil {
+		return nil, err
+	}
+	return u.UpdateArticle(ctx, UpdateArticleInput{
+		ArticleID: articleID,
+		Title:     &revision.Title,
+		Content:   &revision.Content,
+		EditorID:  editorID,
+	})
+}
+
+// newSlug: タイトルから作成者の他の記事と重複しないスラッグを作成
+// articleIDの記事が過去に使っていたスラッグは再利用できる
+func (u *articleUsecase) newSlug(ctx context.Context, userID uint, articleID uint, title string) (string, error) {
+	base := model.SlugFromTitle(title)
+	slugs, err := u.dbRepo.ListArticleSlugs(ctx, userID, base)
+	if err != nil {
+		return "", err
+	}
+	taken := make(map[string]bool, len(slugs))
+	for _, s := range slugs {
+		if s.ArticleID != articleID {
+			taken[s.Slug] = true
+		}
+	}
+	return model.UniqueSlug(base, func(slug string) bool { return taken[slug] }), nil
+}
+
+// recordRevision: 作成・更新後の記事の内容を履歴として保存
+func (u *articleUsecase) recordRevision(ctx context.Context, article *model.Article, editorID uint) error {
+	revision := &model.ArticleRevision{
+		ArticleID: article.ID,
+		Title:     article.Title,
+		Content:   article.Content,
+		Status:    article.Status,
+	}
+	if editorID != 0 {
+		revision.EditorID = &editorID
+	}
+	return u.revisionRepo.CreateRevision(ctx, revision)
+}
+
+// normalizeTags: タグ名の前後の空白を除いて小文字にそろえ、重複を除く
+func normalizeTags(names []string) ([]string, error) {
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			return nil, apperror.Validation("tag must not be empty")
+		}
+		if utf8.RuneCountInString(name) > maxTagLength {
+			return nil, apperror.Validation(fmt.Sprintf("tag must be at most %d characters: %q", maxTagLength, name))
+		}
+		if !slices.Contains(normalized, name) {
+			normalized = append(normalized, name)
+		}
+	}
+	if len(normalized) > maxTagsPerArticle {
+		return nil, apperror.Validation(fmt.Sprintf("an article can have at most %d tags", maxTagsPerArticle))
+	}
+	return normalized, nil
+}
+
+// publishEvent: 記事イベントを購読者に配信
+func (u *articleUsecase) publishEvent(ctx context.Context, eventType event.ArticleEventType, article *model.Article) {
+	if u.publisher == nil {
+		return
+	}
+	u.publisher.Publish(ctx, event.ArticleEvent{Type: eventType, Article: *article})
+}
+
 // ReindexSearchEngine: 検索エンジンのインデックス再構築
 func (u *articleUsecase) ReindexSearchEngine() error {
 	// 新しいインデックスを作成
@@ -197,10 +729,18 @@ func (u *articleUsecase) SeedArticles(userID uint) ([]model.Article, error) {
 
 	var articles []model.Article
 	for _, article := range seedArticles {
+		slug, err := u.newSlug(context.Background(), userID, 0, article.Title)
+		if err != nil {
+			return nil, err
+		}
+		article.Slug = slug
 		createdArticle, err := u.dbRepo.CreateArticle(context.Background(), &article)
 		if err != nil {
 			return nil, err
 		}
+		if err := u.recordRevision(context.Background(), createdArticle, userID); err != nil {
+			return nil, err
+		}
 
 		articles = append(articles, *createdArticle)
 	}
@@ -0,0 +1,195 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/domain/repository"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxAttachmentsPerArticle = 20  // 1つの記事に添付できるファイル数の上限
+	maxFilenameLength        = 255 // 保存するファイル名の文字数の上限
+	sniffLength              = 512 // 形式の判定に使う先頭のバイト数
+)
+
+// 添付できるファイルの形式と、保存時に付ける拡張子
+// 形式はクライアントの申告ではなくファイルの内容から判定する
+var attachmentExtensions = map[string]string{
+	"image/png":  ".png",
+	"image/jpeg": ".jpg",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type UploadAttachmentInput struct {
+	ArticleID uint
+	UserID    uint
+	Filename  string
+	File      io.Reader
+	Size      int64
+}
+
+type AttachmentUsecase interface {
+	GetAttachmentByID(ctx context.Context, attachmentID uint) (*model.Attachment, error)
+	ListAttachments(ctx context.Context, articleID uint) ([]*model.Attachment, error)
+	// 添付ファイルを取得できるURLを返す
+	AttachmentURL(ctx context.Context, attachment *model.Attachment) (string, error)
+
+	UploadAttachment(ctx context.Context, input UploadAttachmentInput) (*model.Attachment, error)
+	DeleteAttachment(ctx context.Context, attachmentID uint) error
+	// DBから削除済みの添付ファイルの本体を削除する(失敗した場合はログに残して続行する)
+	DeleteAttachmentFiles(ctx context.Context, attachments []*model.Attachment)
+}
+
+type attachmentUsecase struct {
+	dbRepo         repository.AttachmentRepository
+	blobs          repository.BlobStore
+	maxUploadBytes int64
+}
+
+func NewAttachmentUsecase(dbRepo repository.AttachmentRepository, blobs repository.BlobStore, maxUploadBytes int64) AttachmentUsecase {
+	return &attachmentUsecase{
+		dbRepo:         dbRepo,
+		blobs:          blobs,
+		maxUploadBytes: maxUploadBytes,
+	}
+}
+
+// GetAttachmentByID: IDで添付ファイル取得
+func (u *attachmentUsecase) GetAttachmentByID(ctx context.Context, attachmentID uint) (*model.Attachment, error) {
+	ctx, span := tracer.Start(ctx, "AttachmentUsecase.GetAttachmentByID")
+	defer span.End()
+
+	return u.dbRepo.GetAttachmentByID(ctx, attachmentID)
+}
+
+// ListAttachments: 記事の添付ファイル一覧取得
+func (u *attachmentUsecase) ListAttachments(ctx context.Context, articleID uint) ([]*model.Attachment, error) {
+	ctx, span := tracer.Start(ctx, "AttachmentUsecase.ListAttachments")
+	defer span.End()
+
+	return u.dbRepo.ListAttachmentsByArticle(ctx, articleID)
+}
+
+// AttachmentURL: 添付ファイルのURL取得
+func (u *attachmentUsecase) AttachmentURL(ctx context.Context, attachment *model.Attachment) (string, error) {
+	return u.blobs.URL(ctx, attachment.Key)
+}
+
+// UploadAttachment: ファイルを保存して記事に添付
+func (u *attachmentUsecase) UploadAttachment(ctx context.Context, input UploadAttachmentInput) (*model.Attachment, error) {
+	ctx, span := tracer.Start(ctx, "AttachmentUsecase.UploadAttachment")
+	defer span.End()
+
+	if input.Size <= 0 {
+		return nil, apperror.Validation("file must not be empty")
+	}
+	if input.Size > u.maxUploadBytes {
+		return nil, apperror.Validation(fmt.Sprintf("file must be at most %d bytes", u.maxUploadBytes))
+	}
+	count, err := u.dbRepo.CountAttachmentsByArticle(ctx, input.ArticleID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= maxAttachmentsPerArticle {
+		return nil, apperror.Validation(fmt.Sprintf("an article can have at most %d attachments", maxAttachmentsPerArticle))
+	}
+
+	// 先頭のバイト列から形式を判定する
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(input.File, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	head = head[:n]
+	contentType := http.DetectContentType(head)
+	ext, ok := attachmentExtensions[contentType]
+	if !ok {
+		return nil, apperror.Validation(fmt.Sprintf("unsupported file type: %s", contentType))
+	}
+
+	key, err := newAttachmentKey(input.ArticleID, ext)
+	if err != nil {
+		return nil, err
+	}
+	body := io.MultiReader(bytes.NewReader(head), input.File)
+	if err := u.blobs.Put(ctx, key, body, input.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment := &model.Attachment{
+		ArticleID:   input.ArticleID,
+		UserID:      input.UserID,
+		Key:         key,
+		Filename:    normalizeFilename(input.Filename, ext),
+		ContentType: contentType,
+		Size:        input.Size,
+	}
+	createdAttachment, err := u.dbRepo.CreateAttachment(ctx, attachment)
+	if err != nil {
+		// 参照されないファイルを残さない
+		if err := u.blobs.Delete(ctx, key); err != nil {
+			logger.WarnContext(ctx, "⚠️ 添付ファイルの削除に失敗しました", "key", key, "error", err)
+		}
+		return nil, err
+	}
+	return createdAttachment, nil
+}
+
+// DeleteAttachment: 添付ファイル削除
+func (u *attachmentUsecase) DeleteAttachment(ctx context.Context, attachmentID uint) error {
+	ctx, span := tracer.Start(ctx, "AttachmentUsecase.DeleteAttachment")
+	defer span.End()
+
+	attachment, err := u.dbRepo.GetAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		return err
+	}
+	if err := u.dbRepo.DeleteAttachment(ctx, attachmentID); err != nil {
+		return err
+	}
+
+	u.DeleteAttachmentFiles(ctx, []*model.Attachment{attachment})
+	return nil
+}
+
+// DeleteAttachmentFiles: 添付ファイルの本体を削除
+// DBから削除済みのため、ファイルの削除に失敗しても参照されることはない
+func (u *attachmentUsecase) DeleteAttachmentFiles(ctx context.Context, attachments []*model.Attachment) {
+	for _, attachment := range attachments {
+		if err := u.blobs.Delete(ctx, attachment.Key); err != nil {
+			logger.WarnContext(ctx, "⚠️ 添付ファイルの削除に失敗しました", "key", attachment.Key, "error", err)
+		}
+	}
+}
+
+// newAttachmentKey: 推測されにくいファイルの保存先を作成
+func newAttachmentKey(articleID uint, ext string) (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("articles/%d/%s%s", articleID, hex.EncodeToString(buf), ext), nil
+}
+
+// normalizeFilename: ファイル名からディレクトリを除き、長すぎる場合は切り詰める
+func normalizeFilename(name string, ext string) string {
+	name = strings.TrimSpace(filepath.Base(strings.ReplaceAll(name, "\\", "/")))
+	if name == "" || name == "." || name == "/" {
+		return "file" + ext
+	}
+	if utf8.RuneCountInString(name) > maxFilenameLength {
+		name = string([]rune(name)[:maxFilenameLength])
+	}
+	return name
+}
@@ -0,0 +1,64 @@
+package usecase
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/domain/repository"
+)
+
+type ListBookmarksInput struct {
+	UserID  uint
+	AfterID uint // 0の場合は先頭から
+	Limit   int
+}
+
+type BookmarkUsecase interface {
+	// ブックマークを追加または取り消し、記事と変更後にブックマークしているかを返す
+	ToggleBookmark(ctx context.Context, articleID uint, userID uint) (*model.Article, bool, error)
+	// 閲覧できる記事のブックマークと総件数を返す
+	ListBookmarks(ctx context.Context, input ListBookmarksInput) ([]*model.Bookmark, int64, error)
+}
+
+type bookmarkUsecase struct {
+	dbRepo      repository.BookmarkRepository
+	articleRepo repository.ArticleRepository
+}
+
+func NewBookmarkUsecase(dbRepo repository.BookmarkRepository, articleRepo repository.ArticleRepository) BookmarkUsecase {
+	return &bookmarkUsecase{
+		dbRepo:      dbRepo,
+		articleRepo: articleRepo,
+	}
+}
+
+// ToggleBookmark: ブックマークの追加・取り消し(公開中の記事と自分の記事のみ)
+func (u *bookmarkUsecase) ToggleBookmark(ctx context.Context, articleID uint, userID uint) (*model.Article, bool, error) {
+	ctx, span := tracer.Start(ctx, "BookmarkUsecase.ToggleBookmark")
+	defer span.End()
+
+	article, err := getVisibleArticle(ctx, u.articleRepo, articleID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	bookmarked, err := u.dbRepo.ToggleBookmark(ctx, articleID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+	return article, bookmarked, nil
+}
+
+// ListBookmarks: ブックマーク一覧と総件数を取得
+func (u *bookmarkUsecase) ListBookmarks(ctx context.Context, input ListBookmarksInput) ([]*model.Bookmark, int64, error) {
+	ctx, span := tracer.Start(ctx, "BookmarkUsecase.ListBookmarks")
+	defer span.End()
+
+	bookmarks, err := u.dbRepo.ListBookmarks(ctx, input.UserID, input.AfterID, input.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	totalCount, err := u.dbRepo.CountBookmarks(ctx, input.UserID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bookmarks, totalCount, nil
+}
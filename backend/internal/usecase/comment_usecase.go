@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/domain/repository"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// コメント本文の文字数の上限
+const maxCommentLength = 2000
+
+var validCommentStatuses = []string{
+	model.CommentStatusPending,
+	model.CommentStatusApproved,
+	model.CommentStatusHidden,
+}
+
+type ListCommentsInput struct {
+	ArticleID uint
+	ParentID  *uint // nilの場合は返信ではないコメント
+	ViewerID  uint  // 0の場合は未ログイン
+	AfterID   uint  // 0の場合は先頭から
+	Limit     int
+}
+
+type AddCommentInput struct {
+	ArticleID uint
+	ParentID  *uint // 返信先のコメント
+	UserID    uint
+	Body      string
+}
+
+type CommentUsecase interface {
+	GetCommentByID(ctx context.Context, commentID uint) (*model.Comment, error)
+	// 閲覧者に表示できるコメントと総件数を返す
+	ListComments(ctx context.Context, input ListCommentsInput) ([]*model.Comment, int64, error)
+
+	AddComment(ctx context.Context, input AddCommentInput) (*model.Comment, error)
+	EditComment(ctx context.Context, commentID uint, userID uint, body string) (*model.Comment, error)
+	DeleteComment(ctx context.Context, commentID uint, userID uint) error
+	ModerateComment(ctx context.Context, commentID uint, userID uint, status string) (*model.Comment, error)
+}
+
+type commentUsecase struct {
+	dbRepo      repository.CommentRepository
+	articleRepo repository.ArticleRepository
+}
+
+func NewCommentUsecase(dbRepo repository.CommentRepository, articleRepo repository.ArticleRepository) CommentUsecase {
+	return &commentUsecase{
+		dbRepo:      dbRepo,
+		articleRepo: articleRepo,
+	}
+}
+
+// GetCommentByID: IDでコメント取得
+func (u *commentUsecase) GetCommentByID(ctx context.Context, commentID uint) (*model.Comment, error) {
+	ctx, span := tracer.Start(ctx, "CommentUsecase.GetCommentByID")
+	defer span.End()
+
+	return u.dbRepo.GetCommentByID(ctx, commentID)
+}
+
+// ListComments: コメント一覧と総件数を取得
+// 承認済みのコメントは誰でも、承認待ち・非表示のコメントは記事の作成者とコメントの投稿者のみ閲覧可能
+func (u *commentUsecase) ListComments(ctx context.Context, input ListCommentsInput) ([]*model.Comment, int64, error) {
+	ctx, span := tracer.Start(ctx, "CommentUsecase.ListComments")
+	defer span.End()
+
+	article, err := u.articleRepo.GetArticleByID(ctx, int64(input.ArticleID))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	filter := repository.CommentFilter{
+		ArticleID: input.ArticleID,
+		ParentID:  input.ParentID,
+	}
+	if input.ViewerID == 0 || input.ViewerID != article.UserID {
+		filter.Statuses = []string{model.CommentStatusApproved}
+		filter.AuthorID = input.ViewerID
+	}
+
+	comments, err := u.dbRepo.ListComments(ctx, filter, input.AfterID, input.Limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	totalCount, err := u.dbRepo.CountComments(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return comments, totalCount, nil
+}
+
+// AddComment: コメント投稿
+// 記事の作成者のコメントは承認済み、それ以外は承認待ちとして作成する
+func (u *commentUsecase) AddComment(ctx context.Context, input AddCommentInput) (*model.Comment, error) {
+	ctx, span := tracer.Start(ctx, "CommentUsecase.AddComment")
+	defer span.End()
+
+	body, err := normalizeCommentBody(input.Body)
+	if err != nil {
+		return nil, err
+	}
+	article, err := u.articleRepo.GetArticleByID(ctx, int64(input.ArticleID))
+	if err != nil {
+		return nil, err
+	}
+	isArticleAuthor := article.UserID == input.UserID
+	// 非公開の記事には作成者以外コメントできない
+	if article.Status != "published" && !isArticleAuthor {
+		return nil, apperror.NotFound("article not found")
+	}
+
+	if input.ParentID != nil {
+		parent, err := u.dbRepo.GetCommentByID(ctx, *input.ParentID)
+		if err != nil {
+			return nil, err
+		}
+		if parent.ArticleID != article.ID {
+			return nil, apperror.Validation("parent comment belongs to another article")
+		}
+		// 閲覧できないコメント(承認待ち・非表示)には、コメントの投稿者と記事の作成者以外返信できない
+		// 存在を知られないよう、存在しない場合と同じエラーを返す
+		if parent.Status != model.CommentStatusApproved && parent.UserID != input.UserID && !isArticleAuthor {
+			return nil, apperror.NotFound("comment not found")
+		}
+	}
+
+	status := model.CommentStatusPending
+	if isArticleAuthor {
+		status = model.CommentStatusApproved
+	}
+	comment := &model.Comment{
+		ArticleID: article.ID,
+		ParentID:  input.ParentID,
+		UserID:    input.UserID,
+		Body:      body,
+		Status:    status,
+	}
+	return u.dbRepo.CreateComment(ctx, comment)
+}
+
+// EditComment: コメント編集(投稿者のみ)
+// 承認済みのコメントを記事の作成者以外が編集した場合は、改めて承認待ちにする
+func (u *commentUsecase) EditComment(ctx context.Context, commentID uint, userID uint, body string) (*model.Comment, error) {
+	ctx, span := tracer.Start(ctx, "CommentUsecase.EditComment")
+	defer span.End()
+
+	body, err := normalizeCommentBody(body)
+	if err != nil {
+		return nil, err
+	}
+	comment, err := u.dbRepo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	if comment.UserID != userID {
+		return nil, apperror.Forbidden("only the author can edit this comment")
+	}
+	if comment.Body == body {
+		return comment, nil
+	}
+
+	if comment.Status == model.CommentStatusApproved {
+		article, err := u.articleRepo.GetArticleByID(ctx, int64(comment.ArticleID))
+		if err != nil {
+			return nil, err
+		}
+		if article.UserID != userID {
+			comment.Status = model.CommentStatusPending
+		}
+	}
+	comment.Body = body
+	return u.dbRepo.UpdateComment(ctx, comment)
+}
+
+// DeleteComment: コメント削除(投稿者または記事の作成者のみ、返信も削除される)
+func (u *commentUsecase) DeleteComment(ctx context.Context, commentID uint, userID uint) error {
+	ctx, span := tracer.Start(ctx, "CommentUsecase.DeleteComment")
+	defer span.End()
+
+	comment, err := u.dbRepo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return err
+	}
+	if comment.UserID != userID {
+		article, err := u.articleRepo.GetArticleByID(ctx, int64(comment.ArticleID))
+		if err != nil {
+			return err
+		}
+		if article.UserID != userID {
+			return apperror.Forbidden("only the comment author or the article author can delete this comment")
+		}
+	}
+
+	return u.dbRepo.DeleteComment(ctx, commentID)
+}
+
+// ModerateComment: コメントのモデレーション状態を変更(記事の作成者のみ)
+func (u *commentUsecase) ModerateComment(ctx context.Context, commentID uint, userID uint, status string) (*model.Comment, error) {
+	ctx, span := tracer.Start(ctx, "CommentUsecase.ModerateComment")
+	defer span.End()
+
+	if !slices.Contains(validCommentStatuses, status) {
+		return nil, apperror.Validation(fmt.Sprintf("invalid comment status: %s", status))
+	}
+	comment, err := u.dbRepo.GetCommentByID(ctx, commentID)
+	if err != nil {
+		return nil, err
+	}
+	article, err := u.articleRepo.GetArticleByID(ctx, int64(comment.ArticleID))
+	if err != nil {
+		return nil, err
+	}
+	if article.UserID != userID {
+		return nil, apperror.Forbidden("only the article author can moderate comments")
+	}
+	if comment.Status == status {
+		return comment, nil
+	}
+
+	comment.Status = status
+	updatedComment, err := u.dbRepo.UpdateComment(ctx, comment)
+	if err != nil {
+		return nil, err
+	}
+	logger.InfoContext(ctx, "🛡️ コメントの状態を変更しました", "comment_id", commentID, "status", status)
+	return updatedComment, nil
+}
+
+// normalizeCommentBody: コメント本文の前後の空白を除いて検証する
+func normalizeCommentBody(body string) (string, error) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return "", apperror.Validation("comment body must not be empty")
+	}
+	if utf8.RuneCountInString(body) > maxCommentLength {
+		return "", apperror.Validation(fmt.Sprintf("comment body must be at most %d characters", maxCommentLength))
+	}
+	return body, nil
+}
@@ -0,0 +1,99 @@
+package usecase
+
+import (
+	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
+	"elasticsearch-sample/backend/internal/domain/model"
+	"elasticsearch-sample/backend/internal/domain/repository"
+)
+
+// いいね数の同期ワーカーが1回の実行で検索エンジンに反映する記事数の上限
+const likeCountSyncBatchSize = 500
+
+type LikeUsecase interface {
+	// いいねを追加または取り消し、更新後の記事と変更後にいいねしているかを返す
+	ToggleLike(ctx context.Context, articleID uint, userID uint) (*model.Article, bool, error)
+	// 指定した記事のうち、ユーザーがいいねしている記事のIDを返す
+	LikedArticleIDs(ctx context.Context, userID uint, articleIDs []uint) ([]uint, error)
+
+	// 変更されたいいね数を検索エンジンに反映し、反映した記事数を返す
+	SyncLikeCounts(ctx context.Context) (int, error)
+}
+
+type likeUsecase struct {
+	dbRepo      repository.LikeRepository
+	articleRepo repository.ArticleRepository
+	searchRepo  repository.ArticleSearchRepository
+}
+
+func NewLikeUsecase(dbRepo repository.LikeRepository, articleRepo repository.ArticleRepository, searchRepo repository.ArticleSearchRepository) LikeUsecase {
+	return &likeUsecase{
+		dbRepo:      dbRepo,
+		articleRepo: articleRepo,
+		searchRepo:  searchRepo,
+	}
+}
+
+// ToggleLike: いいねの追加・取り消し(公開中の記事と自分の記事のみ)
+func (u *likeUsecase) ToggleLike(ctx context.Context, articleID uint, userID uint) (*model.Article, bool, error) {
+	ctx, span := tracer.Start(ctx, "LikeUsecase.ToggleLike")
+	defer span.End()
+
+	if _, err := getVisibleArticle(ctx, u.articleRepo, articleID, userID); err != nil {
+		return nil, false, err
+	}
+	liked, err := u.dbRepo.ToggleLike(ctx, articleID, userID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// いいね数を反映した記事を返す(検索エンジンへの反映はSyncLikeCountsで行う)
+	article, err := u.articleRepo.GetArticleByID(ctx, int64(articleID))
+	if err != nil {
+		return nil, false, err
+	}
+	return article, liked, nil
+}
+
+// LikedArticleIDs: ユーザーがいいねしている記事のID取得
+func (u *likeUsecase) LikedArticleIDs(ctx context.Context, userID uint, articleIDs []uint) ([]uint, error) {
+	ctx, span := tracer.Start(ctx, "LikeUsecase.LikedArticleIDs")
+	defer span.End()
+
+	return u.dbRepo.ListLikedArticleIDs(ctx, userID, articleIDs)
+}
+
+// SyncLikeCounts: 検索エンジンに反映していないいいね数を一括で反映
+func (u *likeUsecase) SyncLikeCounts(ctx context.Context) (int, error) {
+	ctx, span := tracer.Start(ctx, "LikeUsecase.SyncLikeCounts")
+	defer span.End()
+
+	counts, err := u.dbRepo.ListUnindexedLikeCounts(ctx, likeCountSyncBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(counts) == 0 {
+		return 0, nil
+	}
+
+	if err := u.searchRepo.UpdateLikeCounts(ctx, counts); err != nil {
+		return 0, err
+	}
+	// 反映中にいいね数が変わった場合は、反映した値と異なるため次回の実行で再度反映される
+	if err := u.dbRepo.MarkLikeCountsIndexed(ctx, counts); err != nil {
+		return 0, err
+	}
+	return len(counts), nil
+}
+
+// getVisibleArticle: ユーザーが閲覧できる記事(公開中または自分の記事)を取得
+func getVisibleArticle(ctx context.Context, articleRepo repository.ArticleRepository, articleID uint, userID uint) (*model.Article, error) {
+	article, err := articleRepo.GetArticleByID(ctx, int64(articleID))
+	if err != nil {
+		return nil, err
+	}
+	if article.Status != "published" && article.UserID != userID {
+		return nil, apperror.NotFound("article not found")
+	}
+	return article, nil
+}
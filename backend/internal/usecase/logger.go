@@ -0,0 +1,5 @@
+package usecase
+
+import "elasticsearch-sample/backend/internal/logging"
+
+var logger = logging.Component("usecase")
@@ -0,0 +1,6 @@
+package usecase
+
+import "go.opentelemetry.io/otel"
+
+// 各Usecaseのメソッドの処理時間をトレースのスパンとして記録する
+var tracer = otel.Tracer("elasticsearch-sample/backend/internal/usecase")
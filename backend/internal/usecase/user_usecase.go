@@ -2,13 +2,22 @@ package usecase
 
 import (
 	"context"
+	"elasticsearch-sample/backend/internal/domain/apperror"
 	"elasticsearch-sample/backend/internal/domain/model"
 	"elasticsearch-sample/backend/internal/domain/repository"
 )
 
+type ProvisionUserInput struct {
+	UID         string
+	DisplayName string
+	Email       string
+}
+
 type UserUsecase interface {
 	GetUserByID(ctx context.Context, id uint) (*model.User, error)
 	GetUserByUID(ctx context.Context, userUID string) (*model.User, error)
+	GetUsersByIDs(ctx context.Context, ids []uint) ([]*model.User, error)
+	ProvisionUser(ctx context.Context, input ProvisionUserInput) (*model.User, error)
 
 	SeedUsers() ([]model.User, error)
 }
@@ -24,6 +33,9 @@ func NewUserUsecase(dbRepo repository.UserRepository) UserUsecase {
 }
 
 func (u *userUsecase) GetUserByID(ctx context.Context, id uint) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.GetUserByID")
+	defer span.End()
+
 	user, err := u.dbRepo.GetUserByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -32,6 +44,9 @@ func (u *userUsecase) GetUserByID(ctx context.Context, id uint) (*model.User, er
 }
 
 func (u *userUsecase) GetUserByUID(ctx context.Context, userUID string) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.GetUserByUID")
+	defer span.End()
+
 	user, err := u.dbRepo.GetUserByUID(ctx, userUID)
 	if err != nil {
 		return nil, err
@@ -39,9 +54,49 @@ func (u *userUsecase) GetUserByUID(ctx context.Context, userUID string) (*model.
 	return user, nil
 }
 
+func (u *userUsecase) GetUsersByIDs(ctx context.Context, ids []uint) ([]*model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.GetUsersByIDs")
+	defer span.End()
+
+	users, err := u.dbRepo.GetUsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// ProvisionUser 認証済みユーザーが未登録なら作成し、プロフィールが変わっていれば更新する
+func (u *userUsecase) ProvisionUser(ctx context.Context, input ProvisionUserInput) (*model.User, error) {
+	ctx, span := tracer.Start(ctx, "UserUsecase.ProvisionUser")
+	defer span.End()
+
+	user, err := u.dbRepo.GetUserByUID(ctx, input.UID)
+	if err != nil && !apperror.Is(err, apperror.CodeNotFound) {
+		return nil, err
+	}
+	// 変更がなければ書き込まない
+	if user != nil &&
+		(input.DisplayName == "" || input.DisplayName == user.DisplayName) &&
+		(input.Email == "" || input.Email == user.Email) {
+		return user, nil
+	}
+
+	provisioned := &model.User{
+		UID:         input.UID,
+		DisplayName: input.DisplayName,
+		Email:       input.Email,
+	}
+	if err := u.dbRepo.UpsertUserByUID(ctx, provisioned); err != nil {
+		return nil, err
+	}
+
+	// 空のクレームは既存の値が残るため、保存された内容を取得し直す
+	return u.dbRepo.GetUserByUID(ctx, input.UID)
+}
+
 func (u *userUsecase) SeedUsers() ([]model.User, error) {
 	seedUsers := []model.User{
-		{UID: "admin123"},
+		{UID: "admin123", IsAdmin: true},
 		{UID: "admin456"},
 	}
 
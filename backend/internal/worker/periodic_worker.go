@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"elasticsearch-sample/backend/internal/logging"
+)
+
+var logger = logging.Component("worker")
+
+// PeriodicWorker 一定間隔でタスクを実行するバックグラウンドワーカー
+type PeriodicWorker struct {
+	name     string
+	interval time.Duration
+	task     func(ctx context.Context) error
+}
+
+func NewPeriodicWorker(name string, interval time.Duration, task func(ctx context.Context) error) *PeriodicWorker {
+	return &PeriodicWorker{
+		name:     name,
+		interval: interval,
+		task:     task,
+	}
+}
+
+// Name ワーカー名を返す
+func (w *PeriodicWorker) Name() string {
+	return w.name
+}
+
+// Run ctxがキャンセルされるまでタスクを定期実行する
+func (w *PeriodicWorker) Run(ctx context.Context) {
+	logger.InfoContext(ctx, "🚀 ワーカーを開始しました", "worker", w.name, "interval", w.interval.String())
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.task(ctx); err != nil {
+			logger.ErrorContext(ctx, "❌ ワーカーの実行に失敗しました", "worker", w.name, "error", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.InfoContext(ctx, "✅ ワーカーを停止しました", "worker", w.name)
+			return
+		case <-ticker.C:
+		}
+	}
+}